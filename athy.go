@@ -268,6 +268,7 @@ func (r *Registrant) FirstAuthority() (dr *FirstAuthority) {
 		if r.R_FA.IsZero() {
 			r.R_FA = new(FirstAuthority)
 			r.R_FA.r_alt_types = r.R_DITProfile.r_alt_types
+			r.R_FA.r_syntax = r.R_DITProfile.r_syntax
 		}
 
 		return r.R_FA
@@ -288,6 +289,7 @@ func (r *Registrant) CurrentAuthority() *CurrentAuthority {
 		if r.R_CA.IsZero() {
 			r.R_CA = new(CurrentAuthority)
 			r.R_CA.r_alt_types = r.R_DITProfile.r_alt_types
+			r.R_CA.r_syntax = r.R_DITProfile.r_syntax
 		}
 
 		return r.R_CA
@@ -308,6 +310,7 @@ func (r *Registrant) Sponsor() *Sponsor {
 		if r.R_SA.IsZero() {
 			r.R_SA = new(Sponsor)
 			r.R_SA.r_alt_types = r.R_DITProfile.r_alt_types
+			r.R_SA.r_syntax = r.R_DITProfile.r_syntax
 		}
 
 		return r.R_SA
@@ -317,15 +320,12 @@ func (r *Registrant) Sponsor() *Sponsor {
 }
 
 /*
-TTL returns the effective time-to-live for the receiver instance, taking
-into account *[DITProfile]-inherited values as well as subtree-based
-(COLLECTIVE) and entry literal values. The output can be used to instruct
-instances of [Cache] when, and when not, to cache an instance.
-
-See [Section 2.2.3.4 of the RADUA I-D] for details related to TTL precedence
-when handling multiple TTL directives.
+TTL returns the literal time-to-live value assigned to the receiver instance,
+or a zero string if unset.
 
-[Section 2.2.3.4 of the RADUA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-radua#section-2.2.3.4
+See [Registrant.ResolveTTL] for the effective time-to-live, which takes into
+account *[DITProfile]-inherited and COLLECTIVE values, and which can be used
+to instruct instances of [Cache] when, and when not, to cache an instance.
 */
 func (r *Registrant) TTL() string {
 	return r.R_TTL
@@ -124,6 +124,162 @@ func ExampleRegistrant_IDGetFunc() {
 	// Output: a35bef04f
 }
 
+func TestRegistrant_CheckSchema(t *testing.T) {
+	reg := myDedicatedProfile.NewRegistrant()
+	reg.SetDN(`registrantID=X,ou=Registrants,o=rA`)
+	reg.FirstAuthority().SetCN(`Jesse Coretta`)
+
+	if v := reg.CheckSchema(); len(v) != 1 ||
+		v[0].Kind != MissingMandatoryType || v[0].Type != `registrantID` {
+		t.Errorf("%s failed: want missing registrantID, got %v", t.Name(), v)
+		return
+	}
+
+	reg.SetID(`X`)
+	if v := reg.CheckSchema(); len(v) > 0 {
+		t.Errorf("%s failed: unexpected violations: %v", t.Name(), v)
+		return
+	}
+
+	se := myDedicatedProfile.NewSubentry()
+	se.SetDN(`cn=Example,ou=Registrations,o=rA`)
+	se.SetCN(`Example`)
+	if v := se.CheckSchema(); len(v) != 1 || v[0].Type != `subtreeSpecification` {
+		t.Errorf("%s failed: want missing subtreeSpecification, got %v", t.Name(), v)
+	}
+}
+
+func TestRegistrations_Dedicate(t *testing.T) {
+	ded := &DITProfile{R_Settings: newProfileSettings()}
+	ded.SetModel(ThreeDimensional)
+	ded.SetRegistrationBase(`ou=Registrations,o=rA`)
+	ded.SetRegistrantBase(`ou=Registrants,o=rA`)
+
+	iso := myCombinedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X660().CombinedCurrentAuthority().SetCN(`Jesse Coretta`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	org.X660().CombinedFirstAuthority().SetCN(`jesse coretta`)
+	org.X660().CombinedSponsor().SetCN(`Sponsoring Org`)
+
+	var n int
+	namer := func(_ ...any) (any, error) {
+		n++
+		return fmt.Sprintf("registrantID=R%d,ou=Registrants,o=rA", n), nil
+	}
+
+	regs := Registrations{iso}
+	conv, err := regs.Dedicate(ded, namer)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if got := conv.Registrants.Len(); got != 2 {
+		t.Errorf("%s failed: want 2 registrants, got %d", t.Name(), got)
+		return
+	} else if ath := conv.Registrants.Index(0); ath.ID() != `R1` ||
+		ath.FirstAuthority().CN() != `jesse coretta` ||
+		ath.CurrentAuthority().CN() != `Jesse Coretta` {
+		t.Errorf("%s failed: authorities not deduplicated:\n%s", t.Name(), ath.LDIF())
+		return
+	} else if !org.Dedicated() || org.X660().FirstAuthorities()[0] != ath.DN() ||
+		org.X660().Sponsors()[0] != `registrantID=R2,ou=Registrants,o=rA` {
+		t.Errorf("%s failed: DN references not rewritten", t.Name())
+		return
+	} else if l := conv.LDIF(); !strings.Contains(l, `currentAuthorityCommonName: Jesse Coretta`) ||
+		strings.Index(l, `dn: n=1,`) < strings.Index(l, `dn: registrantID=R2`) {
+		t.Errorf("%s failed: unexpected LDIF:\n%s", t.Name(), l)
+		return
+	}
+
+	// ... and back again
+	if conv, err = regs.Combine(myCombinedProfile, conv.Registrants); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if len(conv.Registrations) != 2 || len(org.X660().FirstAuthorities()) != 0 ||
+		org.X660().CombinedSponsor().CN() != `Sponsoring Org` ||
+		!strInSlice(`sponsorContext`, org.ObjectClasses()) {
+		t.Errorf("%s failed: combined conversion incomplete:\n%s", t.Name(), conv.LDIF())
+		return
+	}
+
+	for idx, err := range []error{
+		func() (err error) { _, err = regs.Dedicate(myCombinedProfile); return }(),
+		func() (err error) { _, err = regs.Combine(ded, nil); return }(),
+		func() (err error) {
+			org.X660().R_SAuthyDN = []string{`registrantID=X,ou=Registrants,o=rA`}
+			_, err = regs.Combine(myCombinedProfile, nil)
+			return
+		}(),
+	} {
+		if err == nil {
+			t.Errorf("%s[%d] failed: expected error", t.Name(), idx)
+		}
+	}
+}
+
+func TestRegistrants_Merge(t *testing.T) {
+	a := myDedicatedProfile.NewRegistrant()
+	a.SetDN(`registrantID=A,ou=Registrants,o=rA`)
+	a.SetID(`A`)
+	a.FirstAuthority().SetCN(`Jesse Coretta`)
+	a.FirstAuthority().SetO(`Example Org`)
+	a.FirstAuthority().SetTel(`+1 555 555 1212`)
+	a.FirstAuthority().SetEmail(`jesse@example.com`)
+
+	b := myDedicatedProfile.NewRegistrant()
+	b.SetDN(`registrantID=B,ou=Registrants,o=rA`)
+	b.SetID(`B`)
+	b.CurrentAuthority().SetCN(`jesse  CORETTA`)
+	b.CurrentAuthority().SetO(`example org`)
+	b.CurrentAuthority().SetEmail(`Jesse@Example.com`)
+
+	c := myDedicatedProfile.NewRegistrant()
+	c.SetDN(`registrantID=C,ou=Registrants,o=rA`)
+	c.SetID(`C`)
+	c.Sponsor().SetCN(`Someone Else`)
+	c.Sponsor().SetO(`Example Org`)
+
+	regs := Registrations{myDedicatedProfile.NewRegistration()}
+	regs[0].SetDN(`n=1,ou=Registrations,o=rA`)
+	regs[0].X660().SetFirstAuthorities(b.DN())
+	regs[0].X660().SetCurrentAuthorities(b.DN())
+	regs[0].X660().SetCurrentAuthorities(a.DN())
+
+	ants := Registrants{b, c, a}
+	// a's telephone number is absent from b, and
+	// is therefore scored as a mismatch.
+	if score := a.Similarity(b); score != 0.9 {
+		t.Errorf("%s failed: want similarity 0.9, got %f", t.Name(), score)
+		return
+	} else if n := a.completeness(); n != 5 {
+		t.Errorf("%s failed: want completeness 5, got %d", t.Name(), n)
+		return
+	} else if dups := ants.Duplicates(0.9); len(dups) != 1 || dups[0].A != b || dups[0].B != a {
+		t.Errorf("%s failed: unexpected duplicates %v", t.Name(), dups)
+		return
+	}
+
+	survivor, err := ants.Merge(regs, b, a)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if survivor != a || ants.Len() != 2 ||
+		a.CurrentAuthority().CN() != `jesse  CORETTA` ||
+		a.FirstAuthority().Tel() != `+1 555 555 1212` {
+		t.Errorf("%s failed: unexpected survivor:\n%s", t.Name(), survivor.LDIF())
+		return
+	} else if fa, ca := regs[0].X660().FirstAuthorities(), regs[0].X660().CurrentAuthorities(); len(fa) != 1 || fa[0] != a.DN() || len(ca) != 1 || ca[0] != a.DN() {
+		t.Errorf("%s failed: DN references not rewritten: %v, %v", t.Name(), fa, ca)
+		return
+	}
+
+	if _, err = ants.Merge(regs, a, b); err == nil {
+		t.Errorf("%s failed: expected error merging absent registrant", t.Name())
+	}
+}
+
 func TestAuthority_codecov(t *testing.T) {
 	for idx, err := range []error{
 		bogusRegistrant_codecov(),
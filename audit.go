@@ -0,0 +1,151 @@
+package radir
+
+/*
+audit.go contains opt-in audit stamping of registrations.
+*/
+
+import "time"
+
+/*
+UseAuditStamps declares whether *[Registration] instances governed by the
+receiver shall be stamped automatically with "[registrationCreated]" and
+"[registrationModified]" values.
+
+When enabled, each *[Registration] initialized by way of [DITProfile.NewRegistration]
+-- and, by extension, [Registration.NewChild], [Registration.NewSibling] and
+[Registration.Allocate] -- is assigned a "[registrationCreated]" value. Any
+subsequent change made through a Set* method of the *[Registration], or of
+any of its embedded types, appends a "[registrationModified]" value.
+
+A "[registrationModified]" value is not appended if it is identical to the
+"[registrationCreated]" value or to the most recent "[registrationModified]"
+value, as all stamps bear a precision of one second. Thus, a burst of changes
+(such as those made during initialization) results in no more than a single
+stamp. Explicit assignment of either type is never stamped.
+
+The optional history value limits the number of "[registrationModified]"
+values retained, discarding the oldest values once exceeded. A value of
+zero (0), which is the default, imposes no limit.
+
+Stamps are derived from the package clock (see [time.Now]), or from the
+clock assigned via [DITProfile.SetAuditClock]. This feature is disabled by
+default.
+
+[registrationCreated]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.11
+[registrationModified]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.12
+*/
+func (r *DITProfile) UseAuditStamps(stamp bool, history ...int) {
+	if !r.IsZero() {
+		r.r_audit = stamp
+		r.r_history = 0
+		if len(history) > 0 && history[0] > 0 {
+			r.r_history = history[0]
+		}
+	}
+}
+
+/*
+SetAuditClock assigns the input clock function to the receiver for use in
+audit stamping. A nil clock restores use of the package clock. See also
+[DITProfile.UseAuditStamps].
+*/
+func (r *DITProfile) SetAuditClock(clock func() time.Time) {
+	if !r.IsZero() {
+		r.r_clock = clock
+	}
+}
+
+/*
+auditStamp returns the current generalized time value, as derived from
+the clock of the receiver.
+*/
+func (r *DITProfile) auditStamp() string {
+	clock := now
+	if r.r_clock != nil {
+		clock = r.r_clock
+	}
+
+	return t2gt(clock())
+}
+
+/*
+auditing returns the governing *[DITProfile] of the receiver if audit
+stamping is in effect, else nil.
+*/
+func (r *Registration) auditing() (prof *DITProfile) {
+	if !r.IsZero() && !r.R_DITProfile.IsZero() && r.R_DITProfile.r_audit {
+		prof = r.R_DITProfile
+	}
+
+	return
+}
+
+func (r *Registration) stampCreated() {
+	if prof := r.auditing(); prof != nil {
+		r.Supplement().R_Created = prof.auditStamp()
+	}
+}
+
+func (r *Registration) stampModified() {
+	prof := r.auditing()
+	if prof == nil {
+		return
+	}
+
+	ts := prof.auditStamp()
+	x := r.Supplement()
+	if n := len(x.R_Modified); x.R_Created == ts || (n > 0 && x.R_Modified[n-1] == ts) {
+		return
+	}
+
+	prof.appendModified(x, ts)
+}
+
+/*
+appendModified appends the input "registrationModified" value to the input
+*[Supplement] instance, discarding the oldest values should the history
+limit of the receiver be exceeded.
+*/
+func (r *DITProfile) appendModified(x *Supplement, ts string) {
+	x.R_Modified = append(x.R_Modified, ts)
+	if n := len(x.R_Modified); r.r_history > 0 && n > r.r_history {
+		x.R_Modified = append([]string{}, x.R_Modified[n-r.r_history:]...)
+	}
+}
+
+/*
+stampModified appends a "registrationModified" value to the *[Registration]
+which owns the input instance following a change of the input attribute
+type, if audit stamping is in effect.
+*/
+func stampModified(instance any, tag string) {
+	if eq(tag, `registrationCreated`) || eq(tag, `registrationModified`) {
+		return
+	}
+
+	var reg *Registration
+	switch tv := instance.(type) {
+	case *Registration:
+		reg = tv
+	case *X660:
+		reg = tv.r_reg
+	case *X667:
+		reg = tv.r_reg
+	case *X680:
+		reg = tv.r_reg
+	case *X690:
+		reg = tv.r_reg
+	case *Supplement:
+		reg = tv.r_reg
+	case *Spatial:
+		reg = tv.r_reg
+	case *FirstAuthority:
+		reg = tv.r_reg
+	case *CurrentAuthority:
+		reg = tv.r_reg
+	case *Sponsor:
+		reg = tv.r_reg
+	}
+
+	reg.stampModified()
+}
@@ -68,6 +68,8 @@ type CurrentAuthority struct {
 	R_URI_alt    []string `ldap:"labeledURI"`               // RFC 2079 § 2
 
 	r_alt_types bool
+	r_syntax    bool
+	r_reg       *Registration // owning registration ("Combined Registrants Policy" only)
 }
 
 /*
@@ -654,6 +656,19 @@ func (r *CurrentAuthority) StartTimeGetFunc(getfunc GetOrSetFunc) (any, error) {
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `currentAuthorityStartTimestamp`)
 }
 
+/*
+Start returns the [GeneralizedTime] form of the start time assigned to
+the receiver instance. A zero instance is returned if the value is unset
+or cannot be parsed.
+*/
+func (r *CurrentAuthority) Start() (gt GeneralizedTime) {
+	if !r.IsZero() {
+		gt, _ = ParseGeneralizedTime(r.R_StartTime)
+	}
+
+	return
+}
+
 /*
 Auxiliary returns the static string value "[currentAuthorityContext]" as a
 convenient means of determining the AUXILIARY class associated with an
@@ -4,6 +4,8 @@ package radir
 cfg.go handles all elements pertaining to RA DUA configuration.
 */
 
+import "time"
+
 /*
 SupportedModels is a string slice global variable meant to house all
 directory model numeric OIDs which are supported for the implementation
@@ -86,7 +88,7 @@ type DITProfile struct {
 	R_RegBase  []string `ldap:"rARegistrationBase"` // RASCHEMA 2.3.95
 	R_AthyBase []string `ldap:"rARegistrantBase"`   // RASCHEMA 2.3.96
 	R_Mail     []string `ldap:"rAServiceMail"`      // RASCHEMA 2.3.98
-	R_URI      []string `ldap:"rAServiceURIs"`      // RASCHEMA 2.3.99
+	R_URI      []string `ldap:"rAServiceURI"`       // RASCHEMA 2.3.99
 	R_OC       []string `ldap:"objectClass"`
 
 	// The collective form of the TTL (c-rATTL) is not
@@ -103,6 +105,20 @@ type DITProfile struct {
 	// Make a note of our dedicated type policy (draft or RFC).
 	r_alt_types bool
 
+	// Whether values are subject to opt-in syntax checks.
+	r_syntax bool
+
+	// Opt-in audit stamping of registrations.
+	r_audit   bool
+	r_history int              // maximum registrationModified values
+	r_clock   func() time.Time // nil for the package clock
+
+	// Opt-in conveyance of settings; see UseSettingValues.
+	r_settings bool
+
+	// OID prefix routing rules; see SetOIDRoute.
+	r_routes []oidRoute
+
 	r_bsel [2]int
 }
 
@@ -227,12 +243,52 @@ receiver instance.
 Alternatively, if the user has fashioned an alternative method of the
 same signature, this may be supplied instead.
 
+Any settings conveyed by the entry are assigned to the underlying [ProfileSettings]
+instance, which is initialized if nil. See [DefineSetting] for details.
+
 [go-ldap/v3 Entry.Unmarshal]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#Entry.Unmarshal
 [Entry]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#Entry
 */
 func (r *DITProfile) Marshal(meth func(any) error) (err error) {
+	if r.IsZero() {
+		return
+	} else if meth == nil {
+		err = NilMethodErr
+		return
+	}
+
+	var carrier settingCarrier
+	if err = meth(r); err == nil {
+		err = meth(&carrier)
+	}
+
+	if err == nil && len(carrier.R_Vals) > 0 {
+		if r.R_Settings.IsZero() {
+			r.R_Settings = newProfileSettings()
+		}
+		err = r.R_Settings.parseLDAP(carrier.R_Vals)
+	}
+
+	return
+}
+
+/*
+Unmarshal transports values from the receiver into an instance of
+map[string][]string, which can subsequently be fed to the [go-ldap/v3
+NewEntry] function.
+
+Settings declared through [DefineSetting] are included if enabled through
+[DITProfile.UseSettingValues]. See [DITProfile.Marshal] for the inverse
+operation.
+
+[go-ldap/v3 NewEntry]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#NewEntry
+*/
+func (r *DITProfile) Unmarshal() (outer map[string][]string) {
 	if !r.IsZero() {
-		err = meth(r)
+		outer = unmarshalStruct(r, make(map[string][]string))
+		if vals := r.settingValues(); len(vals) > 0 {
+			outer[settingType] = vals
+		}
 	}
 
 	return
@@ -267,6 +323,10 @@ func (r *DITProfile) LDIF() (l string) {
 		}
 
 		bld.WriteString(toLDIF(r))
+		for _, val := range r.settingValues() {
+			bld.WriteString(settingType + `: ` + val)
+			bld.WriteRune(10)
+		}
 		bld.WriteRune(10)
 
 		l = bld.String()
@@ -324,6 +384,173 @@ func (r *DUAConfig) NumProfile() (num int) {
 	return
 }
 
+/*
+EntryFetcher is a closure signature used to obtain the (unexecuted)
+[go-ldap/v3 Entry.Unmarshal] method instance -- or any alternative method
+of the same signature -- for the entry bearing the input DN.
+
+A zero string DN denotes the RA DSA's Root DSE. An error should be returned
+if the entry could not be retrieved.
+
+See also [BootstrapDUAConfig].
+
+[go-ldap/v3 Entry.Unmarshal]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#Entry.Unmarshal
+*/
+type EntryFetcher func(dn string) (meth func(any) error, err error)
+
+/*
+BootstrapDUAConfig returns an instance of *[DUAConfig] alongside an error
+following an attempt to derive a complete RA DUA configuration through the
+input [EntryFetcher] instance.
+
+The Root DSE is fetched first and marshaled into the return instance by way
+of [DUAConfig.Marshal]. Each "[rADITProfile]" DN value found therein is then
+fetched in turn, and marshaled into a new *[DITProfile] instance appended to
+the "Profiles" struct field. Profiles which bear no DN of their own are
+assigned the DN through which they were fetched, and duplicate DN values are
+fetched only once.
+
+If one or more profiles were fetched, the "DSE" struct field is set to nil,
+as described by [DUAConfig.Profile].
+
+Errors returned by the fetcher are returned as-is, while [NilMethodErr] is
+returned if the fetcher returns a nil method. An instance of [DUAConfigValidityErr]
+is returned if the resulting configuration does not satisfy [DUAConfig.Valid].
+In all cases, the instance is returned as far as it was derived.
+
+[rADITProfile]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.94
+*/
+func BootstrapDUAConfig(fetch EntryFetcher) (r *DUAConfig, err error) {
+	r = NewDUAConfig()
+	if fetch == nil {
+		err = wrapErr(DUAConfigValidityErr, "nil fetcher")
+		return
+	}
+
+	var meth func(any) error
+	if meth, err = fetchEntry(fetch, ``); err != nil {
+		return
+	} else if err = r.Marshal(meth); err != nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, dn := range r.R_ProfileDNs {
+		if seen[normDN(dn)] {
+			continue
+		}
+		seen[normDN(dn)] = true
+
+		if meth, err = fetchEntry(fetch, dn); err != nil {
+			return
+		}
+
+		prof := &DITProfile{R_Settings: newProfileSettings()}
+		if err = prof.Marshal(meth); err != nil {
+			return
+		} else if len(prof.R_DN) == 0 {
+			prof.R_DN = dn
+		}
+		r.R_Profiles = append(r.R_Profiles, prof)
+	}
+
+	if len(r.R_Profiles) > 0 {
+		r.R_DSE = nil
+	}
+
+	if !r.Valid() {
+		err = DUAConfigValidityErr
+	}
+
+	return
+}
+
+func fetchEntry(fetch EntryFetcher, dn string) (meth func(any) error, err error) {
+	if meth, err = fetch(dn); err == nil && meth == nil {
+		err = NilMethodErr
+	}
+
+	return
+}
+
+/*
+Marshal returns an error following an attempt to execute the input meth
+"func(any) error" method signature against the receiver instance as well
+as its "DSE" *[DITProfile] instance, which is initialized if nil.
+
+The meth value should be the (unexecuted) [go-ldap/v3 Entry.Unmarshal]
+method instance for the RA DSA's Root DSE, or an alternative method of the
+same signature. Any "[rADITProfile]" values present are assigned to the
+"ProfileDNs" struct field, while all other values are assigned to the "DSE"
+*[DITProfile] instance.
+
+See also [BootstrapDUAConfig] for a means of fetching referenced profiles.
+
+[go-ldap/v3 Entry.Unmarshal]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#Entry.Unmarshal
+[rADITProfile]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.94
+*/
+func (r *DUAConfig) Marshal(meth func(any) error) (err error) {
+	if r == nil {
+		err = DUAConfigValidityErr
+		return
+	} else if meth == nil {
+		err = NilMethodErr
+		return
+	}
+
+	if r.R_DSE.IsZero() {
+		r.R_DSE = &DITProfile{R_Settings: newProfileSettings()}
+	}
+
+	if err = meth(r); err == nil {
+		err = r.R_DSE.Marshal(meth)
+	}
+
+	return
+}
+
+/*
+Valid returns a boolean value indicative of whether the receiver
+configuration instance is considered contextually valid and usable.
+
+Usability is determined based on all of the following:
+
+  - The receiver is not nil
+  - If no profiles were referenced, the "DSE" *[DITProfile] is valid
+  - Otherwise, each *[DITProfile] referenced is valid and bears a unique DN
+  - Each "[rADITProfile]" value refers to a *[DITProfile] instance present within the receiver
+
+See [DITProfile.Valid] for details on the validity of each *[DITProfile].
+
+[rADITProfile]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.94
+*/
+func (r *DUAConfig) Valid() (valid bool) {
+	if r == nil {
+		return
+	} else if len(r.R_Profiles) == 0 {
+		valid = len(r.R_ProfileDNs) == 0 && r.R_DSE.Valid()
+		return
+	}
+
+	dns := make(map[string]bool)
+	for _, prof := range r.R_Profiles {
+		if !prof.Valid() || len(prof.R_DN) == 0 || dns[normDN(prof.R_DN)] {
+			return
+		}
+		dns[normDN(prof.R_DN)] = true
+	}
+
+	for _, dn := range r.R_ProfileDNs {
+		if !dns[normDN(dn)] {
+			return
+		}
+	}
+
+	valid = true
+
+	return
+}
+
 /*
 Settings returns the underlying instance of [ProfileSettings].
 */
@@ -385,6 +612,42 @@ func (r *DITProfile) UseAltAuthorityTypes(alt bool) {
 	}
 }
 
+/*
+UseSyntaxChecks declares whether certain values assigned to instances of
+*[FirstAuthority], *[CurrentAuthority], *[Sponsor] and *[Supplement] shall
+be subject to syntax checks. By default, no such checks are performed. The
+following syntaxes are checked when enabled:
+
+  - ISO 3166-1 alpha-2 country codes and English short country names
+  - [ITU-T Rec. E.123] telephone, facsimile and mobile numbers
+  - [RFC 5322] mail addresses
+  - Absolute URIs, optionally followed by a label in the case of [labeledURI]
+  - [Generalized Time] values
+  - "[registrationStatus]" values, per [RFC 2578 § 2]
+
+Values which fail such checks are rejected with an instance of *[SyntaxError].
+
+In addition, the assignment of a country code to an authority instance will
+result in the automatic assignment of the corresponding country name, if
+unset, and vice versa.
+
+As with [DITProfile.UseAltAuthorityTypes], this setting is conveyed to new
+instances at the time of their initialization, and does not affect those
+instances already initialized.
+
+[ITU-T Rec. E.123]: https://www.itu.int/rec/T-REC-E.123
+[RFC 5322]: https://datatracker.ietf.org/doc/html/rfc5322#section-3.4.1
+[labeledURI]: https://datatracker.ietf.org/doc/html/rfc2079#section-2
+[Generalized Time]: https://datatracker.ietf.org/doc/html/rfc4517#section-3.3.13
+[registrationStatus]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.14
+[RFC 2578 § 2]: https://datatracker.ietf.org/doc/html/rfc2578#section-2
+*/
+func (r *DITProfile) UseSyntaxChecks(check bool) {
+	if !r.IsZero() {
+		r.r_syntax = check
+	}
+}
+
 /*
 RegistrationTarget sets the preferred Registration Base within the receiver.
 
@@ -964,12 +1227,15 @@ func (r *DITProfile) NewRegistration(root ...bool) *Registration {
 
 	oc = append(oc, soc)
 
-	return &Registration{
+	reg := &Registration{
 		R_OC:         oc,
 		R_SOC:        soc,
 		R_DITProfile: r,
 		r_root:       new(registeredRoot),
 	}
+	reg.stampCreated()
+
+	return reg
 }
 
 /*
@@ -1013,6 +1279,10 @@ type. Instances of this type are found within instances of *[DITProfile].
 This type merely exists for the sake of client optimization and convenience,
 and does not extend from any logic in the I-D series.
 
+Settings may be declared with a type and default value through [DefineSetting],
+following which they may be validated, loaded from JSON or the environment,
+and conveyed alongside the *[DITProfile] entry.
+
 Instances of this type are not thread-safe.
 */
 type ProfileSettings map[string]any
@@ -1024,12 +1294,19 @@ func newProfileSettings() *ProfileSettings {
 
 /*
 Value returns the unasserted value associated with key alongside a
-Boolean value indicative of a successful key match.
+Boolean value indicative of a successful key match. If the key is unset,
+but has been declared through [DefineSetting] with a default value, the
+default value is returned.
 
 Case is significant in the matching process.
 */
 func (r *ProfileSettings) Value(key string) (value any, ok bool) {
-	value, ok = (*r)[key]
+	if value, ok = (*r)[key]; !ok {
+		if def, defined := settingDef(key); defined && def.Default != nil {
+			value, ok = def.Default, true
+		}
+	}
+
 	return
 }
 
@@ -1040,7 +1317,7 @@ a Boolean value indicative of a successful key match and value assertion.
 Case is significant in the matching process.
 */
 func (r *ProfileSettings) BoolValue(key string) (value, ok bool) {
-	if avalue, aok := (*r)[key]; aok {
+	if avalue, aok := r.Value(key); aok {
 		value, ok = avalue.(bool)
 	}
 
@@ -1054,7 +1331,7 @@ a Boolean value indicative of a successful key match and value assertion.
 Case is significant in the matching process.
 */
 func (r *ProfileSettings) StringValue(key string) (value string, ok bool) {
-	if avalue, aok := (*r)[key]; aok {
+	if avalue, aok := r.Value(key); aok {
 		value, ok = avalue.(string)
 	}
 
@@ -1069,7 +1346,7 @@ assertion.
 Case is significant in the matching process.
 */
 func (r *ProfileSettings) StringSliceValue(key string) (value []string, ok bool) {
-	if avalue, aok := (*r)[key]; aok {
+	if avalue, aok := r.Value(key); aok {
 		value, ok = avalue.([]string)
 	}
 
@@ -1,8 +1,12 @@
 package radir
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func ExampleDITProfile_RegistrationBase_multi() {
@@ -147,6 +151,207 @@ func ExampleDITProfile_Settings() {
 	// Output: 4 fields found
 }
 
+func TestDITProfile_UseSyntaxChecks(t *testing.T) {
+	prof := &DITProfile{R_Settings: newProfileSettings()}
+	prof.SetModel(ThreeDimensional)
+	prof.SetRegistrationBase(`ou=Registrations,o=rA`)
+	prof.SetRegistrantBase(`ou=Registrants,o=rA`)
+
+	// Checks are disabled by default
+	lax := prof.NewRegistrant()
+	if err := lax.FirstAuthority().SetTel(`call me maybe`); err != nil {
+		t.Errorf("%s failed: unexpected error: %v", t.Name(), err)
+		return
+	}
+
+	prof.UseSyntaxChecks(true)
+	regi := prof.NewRegistrant()
+	fa := regi.FirstAuthority()
+
+	for idx, good := range []struct {
+		set func(...any) error
+		val any
+	}{
+		{fa.SetTel, `+1 555 555 0100`},
+		{fa.SetFax, `(0607) 123 4567`},
+		{fa.SetMobile, `+44-20-7946-0958`},
+		{fa.SetEmail, `jesse.coretta@icloud.com`},
+		{fa.SetURI, `https://www.example.com/oid`},
+		{fa.SetStartTime, `20130105013105Z`},
+		{regi.Sponsor().SetCO, `Canada`},
+	} {
+		if err := good.set(good.val); err != nil {
+			t.Errorf("%s[%d] failed: unexpected error: %v", t.Name(), idx, err)
+			return
+		}
+	}
+
+	if err := fa.SetC(`us`); err != nil {
+		t.Errorf("%s failed: unexpected error: %v", t.Name(), err)
+		return
+	} else if co := fa.CO(); co != `United States of America` {
+		t.Errorf("%s failed: country name not filled; got '%s'", t.Name(), co)
+		return
+	} else if c := regi.Sponsor().C(); c != `CA` {
+		t.Errorf("%s failed: country code not filled; got '%s'", t.Name(), c)
+		return
+	}
+
+	reg := prof.NewRegistration()
+	for idx, bad := range []struct {
+		set func(...any) error
+		val string
+		err error
+	}{
+		{fa.SetC, `XX`, InvalidCountryErr},
+		{fa.SetCO, `Atlantis`, InvalidCountryErr},
+		{fa.SetTel, `555-CALL-NOW`, InvalidTelephoneErr},
+		{fa.SetTel, `+0 555 0100`, InvalidTelephoneErr},
+		{fa.SetFax, `(0607 123 4567`, InvalidTelephoneErr},
+		{fa.SetEmail, `Jesse <jesse@example.com>`, InvalidMailErr},
+		{fa.SetURI, `/relative/path`, InvalidURIErr},
+		{fa.SetEndTime, `yesterday`, InvalidGTErr},
+		{reg.Supplement().SetStatus, `active`, InvalidStatusErr},
+		{reg.Supplement().SetCreateTime, `2013`, InvalidGTErr},
+	} {
+		var serr *SyntaxError
+		if err := bad.set(bad.val); !errors.As(err, &serr) || !errors.Is(err, bad.err) {
+			t.Errorf("%s[%d] failed: want %v, got %v", t.Name(), idx, bad.err, err)
+			return
+		} else if serr.Value != bad.val {
+			t.Errorf("%s[%d] failed: want value '%s', got '%s'", t.Name(), idx, bad.val, serr.Value)
+			return
+		}
+	}
+
+	if err := reg.Supplement().SetStatus(`deprecated`); err != nil {
+		t.Errorf("%s failed: unexpected error: %v", t.Name(), err)
+	}
+}
+
+func TestDITProfile_UseAuditStamps(t *testing.T) {
+	prof := &DITProfile{R_Settings: newProfileSettings()}
+	prof.SetModel(ThreeDimensional)
+	prof.SetRegistrationBase(`ou=Registrations,o=rA`)
+	prof.SetRegistrantBase(`ou=Registrations,o=rA`)
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prof.SetAuditClock(func() time.Time { return clock })
+	prof.UseAuditStamps(true, 2)
+
+	root := prof.NewRegistration(true)
+	root.SetDN(`n=1,ou=Registrations,o=rA`)
+	root.X680().SetN(`1`)
+	if got := root.Supplement().CreateTime(); got != `20240101000000Z` {
+		t.Errorf("%s failed: want creation stamp, got '%s'", t.Name(), got)
+		return
+	} else if got := root.Supplement().ModifyTime(); len(got) != 0 {
+		t.Errorf("%s failed: unexpected modification stamps during creation: %v", t.Name(), got)
+		return
+	}
+
+	for _, set := range []func(...any) error{
+		root.SetDescription,
+		root.X660().SetUnicodeValue,
+		root.X660().CombinedSponsor().SetCN,
+		root.Spatial().SetTopArc,
+	} {
+		clock = clock.Add(time.Hour)
+		if err := set(`value`); err != nil {
+			t.Errorf("%s failed: %v", t.Name(), err)
+			return
+		}
+	}
+
+	child := root.NewChild(`3`, `identified-organization`)
+	if got := root.Supplement().ModifyTime(); len(got) != 2 || got[1] != `20240101040000Z` {
+		t.Errorf("%s failed: unexpected bounded history: %v", t.Name(), got)
+		return
+	} else if got := child.Supplement().CreateTime(); got != `20240101040000Z` {
+		t.Errorf("%s failed: child not stamped: '%s'", t.Name(), got)
+		return
+	}
+
+	prof.UseAuditStamps(false)
+	clock = clock.Add(time.Hour)
+	root.SetDescription(`another`)
+	if got := root.Supplement().ModifyTime(); len(got) != 2 {
+		t.Errorf("%s failed: stamped while disabled: %v", t.Name(), got)
+	}
+}
+
+func TestDUAConfig_CheckIntegrity(t *testing.T) {
+	prof := &DITProfile{R_Settings: newProfileSettings()}
+	prof.SetModel(ThreeDimensional)
+	prof.SetRegistrationBase(`ou=Registrations,o=rA`)
+	prof.SetRegistrantBase(`ou=Registrants,o=rA`)
+	prof.R_DN = `cn=Profile,o=rA`
+
+	dua := &DUAConfig{
+		R_Profiles:   []*DITProfile{prof},
+		R_ProfileDNs: []string{`cn=profile, o=rA`, `cn=Missing,o=rA`},
+	}
+
+	good := prof.NewRegistrant()
+	good.SetDN(`registrantID=A,ou=Registrants,o=rA`)
+	stray := prof.NewRegistrant()
+	stray.SetDN(`registrantID=B,ou=Elsewhere,o=rA`)
+	lonely := prof.NewRegistrant()
+	lonely.SetDN(`registrantID=C,ou=Registrants,o=rA`)
+	lonely.R_Also = []string{`n=1,ou=Registrations,o=rA`}
+
+	reg := prof.NewRegistration()
+	reg.SetDN(`n=1,ou=Registrations,o=rA`)
+	reg.X660().SetFirstAuthorities(`registrantID=a, ou=Registrants, o=rA`)
+	reg.X660().SetCurrentAuthorities(`registrantID=X,ou=Registrants,o=rA`)
+	reg.X660().SetSponsors(stray.DN())
+	reg.Spatial().SetSupArc(`n=0,ou=Registrations,o=rA`)
+	reg.SetSeeAlso(good.DN())
+
+	// Number form subArc values resolve against the children.
+	reg.NewChild(`2`, ``)
+	reg.Spatial().SetSubArc(`2`)
+	reg.Spatial().SetSubArc(`3`)
+
+	want := []IntegrityViolation{
+		{DN: ``, Type: `rADITProfile`, Target: `cn=Missing,o=rA`, Kind: DanglingReference},
+		{DN: reg.DN(), Type: `currentAuthority`, Target: `registrantID=X,ou=Registrants,o=rA`, Kind: DanglingReference},
+		{DN: reg.DN(), Type: `sponsor`, Target: stray.DN(), Kind: OutsideRegistrantBase},
+		{DN: reg.DN(), Type: `supArc`, Target: `n=0,ou=Registrations,o=rA`, Kind: DanglingReference},
+		{DN: reg.DN(), Type: `subArc`, Target: `3`, Kind: DanglingReference},
+		{DN: lonely.DN(), Kind: UnreferencedRegistrant},
+	}
+
+	got := dua.CheckIntegrity(Registrations{reg}, Registrants{good, stray, lonely})
+	if len(got) != len(want) {
+		t.Errorf("%s failed: want %d violations, got %d: %v", t.Name(), len(want), len(got), got)
+		return
+	}
+
+	for idx, v := range got {
+		if v != want[idx] {
+			t.Errorf("%s[%d] failed:\nwant: %s\ngot:  %s", t.Name(), idx, want[idx], v)
+		}
+	}
+
+	// Registrant bases of the DSE are honored alongside those
+	// of the Profiles.
+	dse := &DITProfile{R_Settings: newProfileSettings()}
+	dse.SetModel(ThreeDimensional)
+	dse.SetRegistrantBase(`ou=Elsewhere,o=rA`)
+	dua.R_DSE = dse
+	dua.R_ProfileDNs = nil
+
+	reg.X660().R_CAuthyDN = []string{good.DN()}
+	reg.Spatial().R_SupArc = ``
+	reg.Spatial().R_SubArc = []string{`2`}
+	lonely.R_Also = nil
+	reg.X660().SetSponsors(lonely.DN())
+	if got = dua.CheckIntegrity(Registrations{reg}, Registrants{good, stray, lonely}); len(got) > 0 {
+		t.Errorf("%s failed: unexpected violations with DSE: %v", t.Name(), got)
+	}
+}
+
 func TestDITProfile_codecov(t *testing.T) {
 	myDedicatedProfile.Marshal(func(_ any) error {
 		return nil
@@ -235,3 +440,342 @@ func TestDITProfile_codecov(t *testing.T) {
 	X.Settings().StringValue(`key0`)
 	X.Settings().StringSliceValue(`key2`)
 }
+
+/*
+mapUnmarshaler returns a closure which behaves as a (crude) stand-in for the
+go-ldap/v3 Entry.Unmarshal method, assigning values of the input entry to
+the tagged string and []string fields of the input struct pointer.
+*/
+func mapUnmarshaler(dn string, entry map[string][]string) func(any) error {
+	return func(x any) error {
+		typ, val, ok := getReflectInstances(x)
+		if !ok {
+			return fmt.Errorf("unsupported type %T", x)
+		}
+
+		for i := 0; i < typ.NumField(); i++ {
+			tag := typ.Field(i).Tag.Get(`ldap`)
+			if tag == `` || !typ.Field(i).IsExported() {
+				continue
+			}
+
+			vals := entry[tag]
+			if tag == `dn` {
+				vals = []string{dn}
+			}
+
+			switch fv := val.Field(i); fv.Interface().(type) {
+			case string:
+				if len(vals) > 0 {
+					fv.SetString(vals[0])
+				}
+			case []string:
+				fv.Set(valOf(append([]string{}, vals...)))
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestBootstrapDUAConfig(t *testing.T) {
+	entries := map[string]map[string][]string{
+		``: {
+			`rADITProfile`: {`cn=Active,o=rA`, `cn=Staging,o=rA`, `cn=active, o=rA`},
+		},
+		`cn=Active,o=rA`: {
+			`rADirectoryModel`:   {ThreeDimensional},
+			`rARegistrationBase`: {`ou=Registrations,o=rA`},
+			`rARegistrantBase`:   {`ou=Registrants,o=rA`},
+		},
+		`cn=Staging,o=rA`: {
+			`rADirectoryModel`:   {TwoDimensional},
+			`rARegistrationBase`: {`ou=Registrations,ou=Staging,o=rA`},
+		},
+	}
+
+	var fetched []string
+	fetch := func(dn string) (func(any) error, error) {
+		fetched = append(fetched, dn)
+		if entry, found := entries[dn]; found {
+			return mapUnmarshaler(dn, entry), nil
+		}
+		return nil, errors.New("no such object")
+	}
+
+	dua, err := BootstrapDUAConfig(fetch)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if len(fetched) != 3 {
+		t.Errorf("%s failed: want 3 fetches, got %v", t.Name(), fetched)
+		return
+	} else if dua.R_DSE != nil || dua.NumProfile() != 2 {
+		t.Errorf("%s failed: want 2 profiles sans DSE, got %d", t.Name(), dua.NumProfile())
+		return
+	} else if got := dua.Profile(1).RegistrationBase(); got != `ou=Registrations,ou=Staging,o=rA` {
+		t.Errorf("%s failed: unexpected staging base '%s'", t.Name(), got)
+		return
+	}
+
+	// single DSE-based profile
+	entries[``] = entries[`cn=Active,o=rA`]
+	if dua, err = BootstrapDUAConfig(fetch); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if dua.Profile().Model() != ThreeDimensional || dua.NumProfile() != 1 {
+		t.Errorf("%s failed: unexpected DSE profile %#v", t.Name(), dua.Profile())
+		return
+	}
+
+	// unresolvable and invalid profiles
+	entries[``] = map[string][]string{`rADITProfile`: {`cn=Missing,o=rA`}}
+	if _, err = BootstrapDUAConfig(fetch); err == nil || err.Error() != `no such object` {
+		t.Errorf("%s failed: want fetch error, got %v", t.Name(), err)
+		return
+	}
+
+	entries[``] = map[string][]string{`rADITProfile`: {`cn=Bogus,o=rA`}}
+	entries[`cn=Bogus,o=rA`] = map[string][]string{`rADirectoryModel`: {`1.2.3`}}
+	if _, err = BootstrapDUAConfig(fetch); err != DUAConfigValidityErr {
+		t.Errorf("%s failed: want %v, got %v", t.Name(), DUAConfigValidityErr, err)
+		return
+	}
+
+	if _, err = BootstrapDUAConfig(nil); err == nil {
+		t.Errorf("%s failed: expected error for nil fetcher", t.Name())
+	}
+
+	var nilDUA *DUAConfig
+	if nilDUA.Valid() || nilDUA.Marshal(nil) == nil {
+		t.Errorf("%s failed: nil receiver considered valid", t.Name())
+	}
+}
+
+func TestDUAConfig_GoverningProfile(t *testing.T) {
+	active := &DITProfile{R_Settings: newProfileSettings(), R_DN: `cn=Active,o=rA`}
+	active.SetModel(ThreeDimensional)
+	active.SetRegistrationBase(`ou=Registrations,o=rA`)
+	active.SetRegistrationBase(`ou=Private,ou=Registrations,o=rA`)
+	active.SetRegistrantBase(`ou=Registrants,o=rA`)
+
+	staging := &DITProfile{R_Settings: newProfileSettings(), R_DN: `cn=Staging,o=rA`}
+	staging.SetModel(ThreeDimensional)
+	staging.SetRegistrationBase(`ou=Registrations,ou=Staging,o=rA`)
+	staging.SetRegistrantBase(`ou=Registrants,ou=Staging,o=rA`)
+
+	dua := &DUAConfig{R_Profiles: []*DITProfile{active, staging}}
+
+	for idx, tc := range []struct {
+		dn         string
+		prof       *DITProfile
+		base       int
+		registrant bool
+	}{
+		{`n=1,n=3,ou=Registrations,o=rA`, active, 0, false},
+		{`n=1, ou=Private, ou=registrations, o=rA`, active, 1, false},
+		{`ou=Private,ou=Registrations,o=rA`, active, 1, false},
+		{`registrantID=X,ou=Registrants,o=rA`, active, 0, true},
+		{`n=1,ou=Registrations,ou=Staging,o=rA`, staging, 0, false},
+		{`registrantID=Y,ou=Registrants,ou=Staging,o=rA`, staging, 0, true},
+		{`n=1,xou=Registrations,o=rA`, nil, -1, false},
+		{``, nil, -1, false},
+	} {
+		prof, base, ant := dua.GoverningProfile(tc.dn)
+		if prof != tc.prof || base != tc.base || ant != tc.registrant {
+			t.Errorf("%s[%d] failed: want %v/%d/%t, got %v/%d/%t", t.Name(), idx,
+				tc.prof != nil, tc.base, tc.registrant, prof != nil, base, ant)
+		}
+	}
+
+	dua.GoverningProfile(`n=2,ou=Private,ou=Registrations,o=rA`)
+	if got := active.RegistrationBase(); got != `ou=Private,ou=Registrations,o=rA` {
+		t.Errorf("%s failed: registration base not selected; got '%s'", t.Name(), got)
+		return
+	}
+
+	// OID routing
+	if err := active.SetOIDRoute(`1.3.6.1.4.1`, 5); err == nil {
+		t.Errorf("%s failed: expected error for bogus base index", t.Name())
+		return
+	} else if err = active.SetOIDRoute(`1.3.6.1.4.1.bogus`, 0); err != InvalidOIDErr {
+		t.Errorf("%s failed: want %v, got %v", t.Name(), InvalidOIDErr, err)
+		return
+	}
+
+	active.SetOIDRoute(`1.3.6.1.4.1`, 0)
+	active.SetOIDRoute(`1.3.6.1.4.1.56521`, 0)
+	active.SetOIDRoute(`1.3.6.1.4.1.56521`, 1) // replaces
+	staging.SetOIDRoute(`1.3.6.1.4.1.56521.999`, 0)
+	staging.SetOIDRoute(`2`, 0)
+
+	for idx, tc := range []struct {
+		dot  string
+		prof *DITProfile
+		base int
+	}{
+		{`1.3.6.1.4.1.1`, active, 0},
+		{`1.3.6.1.4.1.56521.1`, active, 1},
+		{`1.3.6.1.4.1.56521`, active, 1},
+		{`1.3.6.1.4.1.56521.999.3`, staging, 0},
+		{`1.3.6.1.4.1.565210`, active, 0},
+		{`2.25`, staging, 0},
+		{`1.2.840`, nil, -1},
+		{`bogus`, nil, -1},
+	} {
+		if prof, base := dua.GoverningProfileByOID(tc.dot); prof != tc.prof || base != tc.base {
+			t.Errorf("%s[%d] failed: want %v/%d, got %v/%d", t.Name(), idx,
+				tc.prof != nil, tc.base, prof != nil, base)
+		}
+	}
+
+	dua.GoverningProfileByOID(`1.3.6.1.4.1.1`)
+	if got := active.RegistrationBase(); got != `ou=Registrations,o=rA` {
+		t.Errorf("%s failed: registration base not selected; got '%s'", t.Name(), got)
+		return
+	}
+
+	// sole profile fallback
+	single := NewFactoryDefaultDUAConfig()
+	if prof, base := single.GoverningProfileByOID(`1.2.840`); prof != single.R_DSE || base != 0 {
+		t.Errorf("%s failed: sole profile not returned for unrouted OID", t.Name())
+	}
+}
+
+func TestProfileSettings_typed(t *testing.T) {
+	defer func() {
+		for _, name := range []string{`cacheSize`, `cacheTTL`, `strict`, `generator`, `searchBases`} {
+			delete(settingDefs, name)
+		}
+	}()
+
+	if err := DefineSetting(SettingDefinition{Name: `bogus`}); err == nil {
+		t.Errorf("%s failed: expected error for unknown type", t.Name())
+		return
+	} else if err = DefineSetting(SettingDefinition{Name: `cacheSize`, Type: IntSetting, Default: `many`}); err == nil {
+		t.Errorf("%s failed: expected error for bad default", t.Name())
+		return
+	}
+
+	positive := func(v any) error {
+		if v.(int) < 1 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}
+
+	if err := DefineSetting(
+		SettingDefinition{Name: `cacheSize`, Type: IntSetting, Default: 64, Check: positive},
+		SettingDefinition{Name: `cacheTTL`, Type: DurationSetting, Default: `5m`},
+		SettingDefinition{Name: `strict`, Type: BoolSetting},
+		SettingDefinition{Name: `generator`, Type: StringSetting, Default: `uuid`},
+		SettingDefinition{Name: `searchBases`, Type: ListSetting},
+	); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if defs := SettingDefinitions(); len(defs) != 5 || defs[0].Name != `cacheSize` {
+		t.Errorf("%s failed: unexpected definitions %v", t.Name(), defs)
+		return
+	}
+
+	s := newProfileSettings()
+	if v, ok := s.IntValue(`cacheSize`); !ok || v != 64 {
+		t.Errorf("%s failed: want default 64, got %d (%t)", t.Name(), v, ok)
+		return
+	} else if d, ok := s.DurationValue(`cacheTTL`); !ok || d != 5*time.Minute {
+		t.Errorf("%s failed: want default 5m, got %s (%t)", t.Name(), d, ok)
+		return
+	} else if _, ok = s.BoolValue(`strict`); ok {
+		t.Errorf("%s failed: unexpected default for 'strict'", t.Name())
+		return
+	}
+
+	s.Set(`cacheSize`, `lots`)
+	if err := s.Validate(); err == nil {
+		t.Errorf("%s failed: expected validation error", t.Name())
+		return
+	} else if err = s.Parse(`cacheSize`, `0`); err == nil {
+		t.Errorf("%s failed: expected check error", t.Name())
+		return
+	} else if err = s.Parse(`cacheSize`, `128`); err != nil || s.Validate() != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	// JSON
+	path := t.TempDir() + `/settings.json`
+	os.WriteFile(path, []byte(`{"cacheTTL":"90s","strict":"true","searchBases":["ou=A","ou=B"],"other":["x"]}`), 0600)
+	if err := s.LoadJSON(path); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if d, _ := s.DurationValue(`cacheTTL`); d != 90*time.Second {
+		t.Errorf("%s failed: want 90s, got %s", t.Name(), d)
+		return
+	} else if other, _ := s.StringSliceValue(`other`); len(other) != 1 {
+		t.Errorf("%s failed: undeclared list not converted", t.Name())
+		return
+	} else if err = s.UnmarshalJSON([]byte(`{"strict":"maybe","generator":"x"}`)); err == nil {
+		t.Errorf("%s failed: expected JSON conversion error", t.Name())
+		return
+	} else if g, _ := s.StringValue(`generator`); g != `uuid` {
+		t.Errorf("%s failed: partial JSON load applied", t.Name())
+		return
+	}
+
+	// environment
+	t.Setenv(`RADUA_CACHE_SIZE`, `256`)
+	t.Setenv(`RADUA_SEARCH_BASES`, `ou=C, ou=D,`)
+	if err := s.LoadEnv(`RADUA_`); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if n, _ := s.IntValue(`cacheSize`); n != 256 {
+		t.Errorf("%s failed: want 256, got %d", t.Name(), n)
+		return
+	} else if bases, _ := s.StringSliceValue(`searchBases`); len(bases) != 2 || bases[1] != `ou=D` {
+		t.Errorf("%s failed: unexpected bases %v", t.Name(), bases)
+		return
+	}
+
+	// LDIF and Unmarshal/Marshal round-trip
+	prof := &DITProfile{R_Settings: s, R_DN: `cn=Profile,o=rA`}
+	prof.SetModel(ThreeDimensional)
+	prof.SetRegistrationBase(`ou=Registrations,o=rA`)
+
+	// Settings are not conveyed unless requested.
+	if l := prof.LDIF(); strings.Contains(l, `rADUASetting`) {
+		t.Errorf("%s failed: settings conveyed without opt-in:\n%s", t.Name(), l)
+		return
+	} else if _, found := prof.Unmarshal()[`rADUASetting`]; found {
+		t.Errorf("%s failed: settings unmarshaled without opt-in", t.Name())
+		return
+	}
+
+	prof.UseSettingValues(true)
+	if l := prof.LDIF(); !strings.Contains(l, "rADUASetting: cacheTTL=1m30s\n") ||
+		!strings.Contains(l, "rADUASetting: searchBases=ou=D\n") || strings.Contains(l, `other=`) {
+		t.Errorf("%s failed: unexpected LDIF:\n%s", t.Name(), l)
+		return
+	}
+
+	entry := prof.Unmarshal()
+	clone := &DITProfile{}
+	clone.UseSettingValues(true)
+	if err := clone.Marshal(mapUnmarshaler(prof.R_DN, entry)); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if strict, _ := clone.Settings().BoolValue(`strict`); !strict {
+		t.Errorf("%s failed: 'strict' not conveyed", t.Name())
+		return
+	} else if bases, _ := clone.Settings().StringSliceValue(`searchBases`); len(bases) != 2 {
+		t.Errorf("%s failed: 'searchBases' not conveyed: %v", t.Name(), bases)
+		return
+	} else if clone.Model() != ThreeDimensional || clone.LDIF() != prof.LDIF() {
+		t.Errorf("%s failed: round-trip mismatch:\n%s\n%s", t.Name(), prof.LDIF(), clone.LDIF())
+		return
+	}
+
+	entry[`rADUASetting`] = []string{`bogus`}
+	if err := clone.Marshal(mapUnmarshaler(prof.R_DN, entry)); err == nil {
+		t.Errorf("%s failed: expected error for malformed setting", t.Name())
+	}
+}
@@ -0,0 +1,580 @@
+package radir
+
+import (
+	"reflect"
+)
+
+/*
+coll.go implements client-side derivation of collective attribute values.
+*/
+
+/*
+Effective returns a new instance of *[Registration] representing the
+effective view of the receiver alongside an error. The effective view
+bears all literal values of the receiver, overlaid with the collective
+values of each *[Subentry] whose subtree specification includes the
+receiver, per [RFC 3671].
+
+This is intended for use with directories which do not support collective
+attributes natively, as described within the [RADUA I-D].
+
+Candidate *[Subentry] instances are gathered from the [Subentries] of the
+receiver and of each of its ancestors, beginning with the receiver and
+moving upwards. Any variadic *[Subentry] input instances, such as those
+residing beneath the registration base, are considered last. Each is
+evaluated using [Subentry.Governs].
+
+When two or more governing subentries provide a single-valued collective
+type, such as "c-rATTL" or "c-supArc", the nearest subentry prevails. The
+values of multi-valued collective types are merged. Collective values
+already present within the receiver, such as those returned by a DSA which
+supports collective attributes, are always preserved.
+
+The "[collectiveExclusions]" values of the receiver are honored, including
+"excludeAllCollectiveAttributes". The DN of each governing subentry is
+added to the "[collectiveAttributeSubentries]" values of the return
+instance.
+
+The receiver is not modified. All components of the return instance, such
+as its *[X660] and *[X680] instances and any combined authorities, are copies
+of those of the receiver. The return instance shares its parent and children
+with the receiver, but is not itself a member of the tree.
+
+[RFC 3671]: https://www.rfc-editor.org/rfc/rfc3671.html
+[RADUA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-radua
+[collectiveExclusions]: https://www.rfc-editor.org/rfc/rfc3671.html#section-2.1
+[collectiveAttributeSubentries]: https://www.rfc-editor.org/rfc/rfc3671.html#section-2.2
+*/
+func (r *Registration) Effective(subentries ...*Subentry) (eff *Registration, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	}
+
+	eff = new(Registration)
+	*eff = *r
+	if !r.R_X660.IsZero() {
+		x := *r.R_X660
+		if !x.R_CFAuthy.IsZero() {
+			fa := *x.R_CFAuthy
+			fa.r_reg, x.R_CFAuthy = eff, &fa
+			copyStringFields(x.R_CFAuthy)
+		}
+		if !x.R_CCAuthy.IsZero() {
+			ca := *x.R_CCAuthy
+			ca.r_reg, x.R_CCAuthy = eff, &ca
+			copyStringFields(x.R_CCAuthy)
+		}
+		if !x.R_CSAuthy.IsZero() {
+			sp := *x.R_CSAuthy
+			sp.r_reg, x.R_CSAuthy = eff, &sp
+			copyStringFields(x.R_CSAuthy)
+		}
+		x.r_reg, eff.R_X660 = eff, &x
+		copyStringFields(eff.R_X660)
+	}
+	if !r.R_X667.IsZero() {
+		x := *r.R_X667
+		x.r_reg, eff.R_X667 = eff, &x
+		copyStringFields(eff.R_X667)
+	}
+	if !r.R_X680.IsZero() {
+		x := *r.R_X680
+		x.r_reg, eff.R_X680 = eff, &x
+		copyStringFields(eff.R_X680)
+	}
+	if !r.R_X690.IsZero() {
+		x := *r.R_X690
+		x.r_reg, eff.R_X690 = eff, &x
+		copyStringFields(eff.R_X690)
+	}
+	if !r.R_Spatial.IsZero() {
+		s := *r.R_Spatial
+		s.r_reg, eff.R_Spatial = eff, &s
+		copyStringFields(eff.R_Spatial)
+	}
+	if !r.R_Extra.IsZero() {
+		e := *r.R_Extra
+		e.r_reg, eff.R_Extra = eff, &e
+		copyStringFields(eff.R_Extra)
+	}
+	copyStringFields(eff)
+
+	excluded := r.collectiveExcluded()
+
+	for _, se := range r.candidateSubentries(subentries...) {
+		var governs bool
+		if governs, err = se.Governs(r); err != nil {
+			eff = nil
+			return
+		} else if !governs {
+			continue
+		}
+
+		if !strInSlice(se.DN(), eff.R_CAS) {
+			eff.R_CAS = append(eff.R_CAS, se.DN())
+		}
+
+		overlayCollective(eff, se, excluded)
+		if !se.R_X660.IsZero() {
+			overlayCollective(eff.X660(), se.R_X660, excluded)
+		}
+		if !se.R_Spatial.IsZero() {
+			overlayCollective(eff.Spatial(), se.R_Spatial, excluded)
+		}
+		if !se.R_Extra.IsZero() {
+			overlayCollective(eff.Supplement(), se.R_Extra, excluded)
+		}
+	}
+
+	return
+}
+
+/*
+candidateSubentries returns the subentries of the receiver and of each
+of its ancestors, nearest first, followed by any extra input instances.
+Duplicates (by DN) are discarded.
+*/
+func (r *Registration) candidateSubentries(extra ...*Subentry) (cands []*Subentry) {
+	var seen []string
+	add := func(se *Subentry) {
+		if !se.IsZero() && !strInSlice(se.DN(), seen) {
+			seen = append(seen, se.DN())
+			cands = append(cands, se)
+		}
+	}
+
+	for reg := r; !reg.IsZero(); reg = reg.Parent() {
+		if ses := reg.r_se; !ses.IsZero() {
+			for i := 0; i < ses.Len(); i++ {
+				add(ses.Index(i))
+			}
+		}
+	}
+
+	for _, se := range extra {
+		add(se)
+	}
+
+	return
+}
+
+/*
+collectiveExcluded returns a closure which reports whether the input
+collective attribute type descriptor is excluded by the receiver's
+"collectiveExclusions" values.
+*/
+func (r *Registration) collectiveExcluded() func(string) bool {
+	var all bool
+	for _, excl := range r.CollectiveExclusions() {
+		if eq(excl, `excludeAllCollectiveAttributes`) || excl == `2.5.18.0` {
+			all = true
+		}
+	}
+
+	return func(at string) (excluded bool) {
+		if excluded = all; !excluded {
+			oid := attributeTypeOID(at)
+			for _, excl := range r.CollectiveExclusions() {
+				if eq(excl, at) || (len(oid) > 0 && excl == oid) {
+					excluded = true
+					break
+				}
+			}
+		}
+
+		return
+	}
+}
+
+/*
+LiteralChange describes the deletion or addition of literal attribute
+values from or to a registration entry, as implied by a collective value
+within a particular subentry.
+*/
+type LiteralChange struct {
+	DN       string   // DN of the affected registration
+	Type     string   // literal attribute type, e.g.: "rATTL"
+	Values   []string // values deleted or added
+	Subentry string   // DN of the subentry bearing the collective value
+}
+
+/*
+CollectivePlan contains the results of a call of [Registration.PlanCollectives].
+*/
+type CollectivePlan struct {
+	// Subentries contains the proposed *[Subentry] instances, each
+	// named beneath the administrative point and bearing a subtree
+	// specification alongside one or more collective values.
+	Subentries Subentries
+
+	// Deletions contains the literal values which become redundant
+	// once the proposed subentries are in effect.
+	Deletions []LiteralChange
+}
+
+/*
+PlanCollectives returns an instance of [CollectivePlan] alongside an error
+following an analysis of the receiver and its progeny for literal values
+which are shared identically by many registrations, and which therefore
+may be factored into collective values within subentries.
+
+The following literal types are considered, each of which corresponds to
+a collective type:
+
+  - "rATTL" (c-rATTL)
+  - "firstAuthority", "currentAuthority" and "sponsor" (c-firstAuthority, c-currentAuthority and c-sponsor)
+  - "discloseTo" (c-discloseTo)
+  - "supArc", "topArc", "minArc" and "maxArc" (c-supArc, c-topArc, c-minArc and c-maxArc)
+
+The receiver serves as the administrative point. For each type, and for
+each registration (beginning with the receiver and moving downwards), the
+following scopes are considered in order of preference:
+
+  - The registration and all of its progeny (base only)
+  - All progeny of the registration (minimum 1)
+  - Only the immediate subordinates of the registration (minimum 1, maximum 1)
+
+The first scope in which every member bears an identical value for the
+type, and which is not already served by another proposal, is proposed.
+Multi-valued types are compared without regard for value order or case.
+The optional threshold input value defines the minimum number of members
+a scope must contain to be proposed, and defaults to two (2).
+
+Proposals sharing the same subtree specification are merged into a single
+*[Subentry]. Each *[Subentry] is named "cn=<cn>-<n>" beneath the receiver
+and is NOT added to the receiver's [Subentries] instance. The receiver and
+its progeny are not modified. A zero plan is returned alongside an error if
+the subtree specification of any proposal cannot be assigned.
+
+This method is only applicable to the [ThreeDimensional] model.
+
+See also [Registration.MaterializeCollectives] for the reverse operation.
+*/
+func (r *Registration) PlanCollectives(cn string, threshold ...int) (plan CollectivePlan, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	} else if r.Profile().Model() != ThreeDimensional {
+		err = InvalidDimensionErr
+		return
+	} else if len(r.DN()) == 0 || len(cn) == 0 {
+		err = InvalidDNErr
+		return
+	}
+
+	min := 2
+	if len(threshold) > 0 && threshold[0] > 0 {
+		min = threshold[0]
+	}
+
+	plan.Subentries = make(Subentries, 0)
+	byspec := make(map[string]*Subentry)
+	for _, ct := range collectiveTypes() {
+		covered := make(map[*Registration]bool)
+		r.planCollective(r, ct, min, covered, func(spec SubtreeSpecification, vals []string, members []*Registration) {
+			if err != nil {
+				return
+			}
+
+			key := spec.String()
+			se, found := byspec[key]
+			if !found {
+				se = r.Profile().NewSubentry()
+				se.SetCN(cn + `-` + itoa(len(plan.Subentries)+1))
+				se.SetDN(`cn=` + se.CN() + `,` + r.DN())
+				if err = se.SetSubtreeSpecification(spec); err != nil {
+					return
+				}
+				byspec[key] = se
+				plan.Subentries = append(plan.Subentries, se)
+			}
+			writeTagValues(ct.se(se), ct.collective, vals)
+
+			for _, member := range members {
+				plan.Deletions = append(plan.Deletions, LiteralChange{
+					DN:       member.DN(),
+					Type:     ct.literal,
+					Values:   ct.values(member),
+					Subentry: se.DN(),
+				})
+			}
+		})
+	}
+
+	if err != nil {
+		plan = CollectivePlan{}
+	}
+
+	return
+}
+
+func (r *Registration) planCollective(admin *Registration, ct collectiveType, min int,
+	covered map[*Registration]bool, propose func(SubtreeSpecification, []string, []*Registration)) {
+
+	base := LocalName(admin.relativeRDNs(r))
+	all := r.progeny(true)
+	subs := all[1:]
+	kids := make([]*Registration, 0)
+	for i := 0; i < r.Children().Len(); i++ {
+		kids = append(kids, r.Children().Index(i))
+	}
+
+	for _, scope := range []struct {
+		members []*Registration
+		spec    SubtreeSpecification
+	}{
+		{all, SubtreeSpecification{Base: base}},
+		{subs, SubtreeSpecification{Base: base, Min: 1}},
+		{kids, SubtreeSpecification{Base: base, Min: 1, Max: 1}},
+	} {
+		if len(scope.members) < min {
+			continue
+		}
+
+		if vals, ok := ct.uniform(scope.members, covered); ok {
+			for _, member := range scope.members {
+				covered[member] = true
+			}
+			propose(scope.spec, vals, scope.members)
+			break
+		}
+	}
+
+	for i := 0; i < r.Children().Len(); i++ {
+		r.Children().Index(i).planCollective(admin, ct, min, covered, propose)
+	}
+}
+
+/*
+MaterializeCollectives writes the collective values of each *[Subentry]
+which governs the receiver, or any of its progeny, into the corresponding
+literal fields of the affected registrations. This is intended for use
+with DSAs which do not support collective attributes, per [RFC 3671].
+
+Candidate *[Subentry] instances are gathered and evaluated in the manner
+described within the [Registration.Effective] documentation, to include
+any variadic input instances. Single-valued literal types are only written
+if zero, while the values of multi-valued types are merged. Collective
+exclusions are honored.
+
+The additions made are returned as instances of [LiteralChange] alongside
+an error.
+
+[RFC 3671]: https://www.rfc-editor.org/rfc/rfc3671.html
+*/
+func (r *Registration) MaterializeCollectives(subentries ...*Subentry) (adds []LiteralChange, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	}
+
+	for _, reg := range r.progeny(true) {
+		excluded := reg.collectiveExcluded()
+		for _, se := range reg.candidateSubentries(subentries...) {
+			var governs bool
+			if governs, err = se.Governs(reg); err != nil {
+				return
+			} else if !governs {
+				continue
+			}
+
+			for _, ct := range collectiveTypes() {
+				src := ct.se(se)
+				cvals := readFieldByTag(ct.collective, src)
+				if len(cvals) == 0 || excluded(ct.collective[:idxr(ct.collective, ';')]) {
+					continue
+				}
+
+				var added []string
+				lvals := ct.values(reg)
+				for _, val := range cvals {
+					if !strInSlice(val, lvals) && (len(lvals) == 0 || ct.multi) {
+						added = append(added, val)
+						lvals = append(lvals, val)
+					}
+				}
+
+				if len(added) > 0 {
+					writeTagValues(ct.reg(reg, true), ct.literal, lvals)
+					adds = append(adds, LiteralChange{
+						DN:       reg.DN(),
+						Type:     ct.literal,
+						Values:   added,
+						Subentry: se.DN(),
+					})
+				}
+			}
+		}
+	}
+
+	return
+}
+
+/*
+collectiveType describes a literal attribute type which bears a collective
+counterpart, alongside the means of reaching the component struct which
+bears both within a *[Registration] or *[Subentry].
+*/
+type collectiveType struct {
+	literal    string // literal tag, e.g.: "rATTL"
+	collective string // collective tag, e.g.: "c-rATTL;collective"
+	multi      bool   // multi-valued
+	reg        func(*Registration, bool) any
+	se         func(*Subentry) any
+}
+
+/*
+collectiveTypes returns all literal attribute types which bear collective
+counterparts, derived from the field tags of each relevant component.
+*/
+func collectiveTypes() (cts []collectiveType) {
+	for _, comp := range []struct {
+		zero any
+		reg  func(*Registration, bool) any
+		se   func(*Subentry) any
+	}{
+		{&Registration{}, func(r *Registration, _ bool) any { return r },
+			func(s *Subentry) any { return s }},
+		{&X660{}, func(r *Registration, init bool) any {
+			if init || !r.R_X660.IsZero() {
+				return r.X660()
+			}
+			return nil
+		}, func(s *Subentry) any { return s.X660() }},
+		{&Spatial{}, func(r *Registration, init bool) any {
+			if init || !r.R_Spatial.IsZero() {
+				return r.Spatial()
+			}
+			return nil
+		}, func(s *Subentry) any { return s.Spatial() }},
+		{&Supplement{}, func(r *Registration, init bool) any {
+			if init || !r.R_Extra.IsZero() {
+				return r.Supplement()
+			}
+			return nil
+		}, func(s *Subentry) any { return s.Supplement() }},
+	} {
+		t := typeOf(comp.zero).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			tag := t.Field(i).Tag.Get("ldap")
+			if !hasPfx(tag, `c-`) || !hasSfx(tag, `;collective`) {
+				continue
+			}
+
+			lit := tag[2:idxr(tag, ';')]
+			if f, err := getFieldByNameTag(comp.zero, lit); err == nil {
+				cts = append(cts, collectiveType{
+					literal:    lit,
+					collective: tag,
+					multi:      f.Kind() == reflect.Slice,
+					reg:        comp.reg,
+					se:         comp.se,
+				})
+			}
+		}
+	}
+
+	return
+}
+
+/*
+values returns the literal values of the receiver type held by reg.
+*/
+func (r collectiveType) values(reg *Registration) (vals []string) {
+	if inst := r.reg(reg, false); inst != nil {
+		vals = readFieldByTag(r.literal, inst)
+	}
+
+	return
+}
+
+/*
+uniform returns the values shared identically by all members alongside
+a Boolean value indicative of success. Members which bear no value, or
+which have already been covered, result in failure.
+*/
+func (r collectiveType) uniform(members []*Registration, covered map[*Registration]bool) (vals []string, ok bool) {
+	var key string
+	for i, member := range members {
+		if covered[member] {
+			return
+		}
+
+		mvals := r.values(member)
+		if len(mvals) == 0 {
+			return
+		}
+
+		norm := make([]string, len(mvals))
+		for j := 0; j < len(mvals); j++ {
+			norm[j] = lc(mvals[j])
+		}
+		sortStrs(norm)
+
+		if i == 0 {
+			key = join(norm, "\x00")
+			vals = mvals
+		} else if join(norm, "\x00") != key {
+			return
+		}
+	}
+
+	ok = len(members) > 0
+
+	return
+}
+
+/*
+progeny returns the receiver and all of its progeny in depth-first order.
+If self is false, the receiver is omitted.
+*/
+func (r *Registration) progeny(self bool) (regs []*Registration) {
+	if r.IsZero() {
+		return
+	}
+
+	if self {
+		regs = append(regs, r)
+	}
+
+	for i := 0; i < r.Children().Len(); i++ {
+		regs = append(regs, r.Children().Index(i).progeny(true)...)
+	}
+
+	return
+}
+
+/*
+relativeRDNs returns the RDN sequence which separates the input descendant
+from the receiver, or a zero string if they are one and the same.
+*/
+func (r *Registration) relativeRDNs(desc *Registration) (rel string) {
+	if dn, sfx := desc.DN(), r.DN(); len(dn) > len(sfx)+1 && eq(dn[len(dn)-len(sfx):], sfx) {
+		rel = dn[:len(dn)-len(sfx)-1]
+	}
+
+	return
+}
+
+/*
+copyStringFields replaces each exported []string field of the input struct
+pointer with a copy, such that it no longer shares its backing array with
+that of the instance from which the struct was copied.
+*/
+func copyStringFields(x any) {
+	typ, val, ok := getReflectInstances(x)
+	if !ok {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		if !typ.Field(i).IsExported() {
+			continue
+		}
+
+		if vals, isStr := val.Field(i).Interface().([]string); isStr && vals != nil {
+			val.Field(i).Set(valOf(append([]string{}, vals...)))
+		}
+	}
+}
@@ -82,6 +82,47 @@ var RegistrationAttributeTypes map[string]string = map[string]string{
 	"unicodeValue":               AttributeTypesOIDPrefix + ".5",
 }
 
+/*
+SingleValueAttributeTypes contains the names of all Attribute Types defined
+within [Section 2.3 of the RASCHEMA I-D] which bear the SINGLE-VALUE clause.
+Those types absent from this manifest are multi-valued.
+
+[Section 2.3 of the RASCHEMA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3
+*/
+var SingleValueAttributeTypes []string = []string{
+	// Registration types (2.3.1 - 2.3.31)
+	"n", "dotNotation", "aSN1Notation", "unicodeValue", "identifier",
+	"registrationCreated", "registrationRange", "registrationStatus",
+	"registrationClassification", "isLeafNode", "isFrozen", "nameAndNumberForm",
+	"supArc", "c-supArc", "topArc", "c-topArc", "leftArc", "minArc", "c-minArc",
+	"rightArc", "maxArc", "c-maxArc",
+
+	// Registrant types (2.3.34 - 2.3.92)
+	"registrantID",
+	"currentAuthorityStartTimestamp", "currentAuthorityCommonName",
+	"currentAuthorityCountryCode", "currentAuthorityCountryName",
+	"currentAuthorityEmail", "currentAuthorityFax", "currentAuthorityLocality",
+	"currentAuthorityMobile", "currentAuthorityOrg", "currentAuthorityPOBox",
+	"currentAuthorityPostalAddress", "currentAuthorityPostalCode",
+	"currentAuthorityState", "currentAuthorityStreet",
+	"currentAuthorityTelephone", "currentAuthorityTitle",
+	"firstAuthorityStartTimestamp", "firstAuthorityEndTimestamp",
+	"firstAuthorityCommonName", "firstAuthorityCountryCode",
+	"firstAuthorityCountryName", "firstAuthorityEmail", "firstAuthorityFax",
+	"firstAuthorityLocality", "firstAuthorityMobile", "firstAuthorityOrg",
+	"firstAuthorityPOBox", "firstAuthorityPostalAddress",
+	"firstAuthorityPostalCode", "firstAuthorityState", "firstAuthorityStreet",
+	"firstAuthorityTelephone", "firstAuthorityTitle",
+	"sponsorStartTimestamp", "sponsorEndTimestamp", "sponsorCommonName",
+	"sponsorCountryCode", "sponsorCountryName", "sponsorEmail", "sponsorFax",
+	"sponsorLocality", "sponsorMobile", "sponsorOrg", "sponsorPOBox",
+	"sponsorPostalAddress", "sponsorPostalCode", "sponsorState",
+	"sponsorStreet", "sponsorTelephone", "sponsorTitle",
+
+	// Configuration and miscellaneous types (2.3.97 - 2.3.103)
+	"rADirectoryModel", "rATTL", "c-rATTL", "registeredUUID", "dotEncoding",
+}
+
 /*
 ConfigurationAttributeTypes contains all Attribute Types defined within
 [Section 2.3 of the RASCHEMA I-D] that are *[DUAConfig] and *[DITProfile]
@@ -391,6 +432,33 @@ var NameForms map[string]string = map[string]string{
 	"nRootArcForm":    NameFormsOIDPrefix + ".1",
 }
 
+/*
+attributeTypeOID returns the numeric OID of the input attribute type
+descriptor, if known to this package. Numeric OIDs are returned as-is,
+while unknown descriptors are returned as a zero string.
+*/
+func attributeTypeOID(at string) (oid string) {
+	if IsNumericOID(at) {
+		oid = at
+		return
+	}
+
+	for _, types := range []map[string]string{
+		RegistrationAttributeTypes,
+		RegistrantAttributeTypes,
+		ConfigurationAttributeTypes,
+	} {
+		for name, num := range types {
+			if eq(name, at) {
+				oid = num
+				return
+			}
+		}
+	}
+
+	return
+}
+
 func resolveAltType(tag string, typ int, alt bool) string {
 	if !alt || !(0 <= typ && typ <= 2) {
 		// If alt attr policy is not used
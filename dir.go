@@ -0,0 +1,953 @@
+package radir
+
+/*
+dir.go contains the Directory interface and its memory-based implementation.
+*/
+
+import "sync"
+
+/*
+Directory is implemented by types which perform LDAP operations upon an RA
+DSA, or a facsimile thereof, in terms native to this package. This allows
+the same DUA logic to be executed against a live DSA or against memory.
+
+Entries are instances of map[string][]string of the form returned by the
+various Unmarshal methods (e.g.: [Registration.Unmarshal]), in which the
+DN is held by the "dn" key.
+
+Errors which correspond to an LDAP result code should be reported such that
+[errors.Is] matches the corresponding error of this package, i.e.:
+
+  - NoSuchObjectErr (32)
+  - InvalidDNErr (34)
+  - NoSuchAttributeErr (16)
+  - AttributeOrValueExistsErr (20)
+  - ObjectClassViolationErr (65)
+  - NotAllowedOnNonLeafErr (66)
+  - NotAllowedOnRDNErr (67)
+  - EntryExistsErr (68)
+  - InvalidFilterErr (malformed search filter)
+
+See [MemoryDirectory] for an implementation which requires no DSA.
+*/
+type Directory interface {
+	// Search returns all entries which reside within the scope (e.g.:
+	// SingleLevelScope) of the base DN and which match the RFC 4515
+	// filter. If attribute types are specified, only those are returned.
+	Search(base string, scope int, filter string, attrs []string) ([]map[string][]string, error)
+
+	// Add creates the input entry beneath its existing superior.
+	Add(entry map[string][]string) error
+
+	// Modify applies the input modifications, all of which bear the
+	// same DN, to the entry as a single operation.
+	Modify(mods ...Modification) error
+
+	// Delete removes the entry bearing the input DN, which must not
+	// have any subordinates.
+	Delete(dn string) error
+
+	// ModifyDN assigns a new RDN to the entry bearing the input DN,
+	// relocating it beneath the new superior DN if non-zero.
+	ModifyDN(dn, newRDN string, deleteOldRDN bool, newSuperior string) error
+}
+
+/*
+MemoryDirectory implements a thread-safe, memory-based [Directory] which
+is backed by *[Registration] trees and, under the terms of the "Dedicated
+Registrants Policy", *[Registrant] instances.
+
+Entries may reside beneath any registration or registrant base of the
+*[DITProfile] in use, or beneath one another. Registrations added through
+the [Directory] methods are linked into the tree of their parent, as are
+subentries into the [Subentries] of their registration; the trees may
+be obtained through [MemoryDirectory.Registrations].
+
+Instances of this type are created using the [NewMemoryDirectory] function.
+*/
+type MemoryDirectory struct {
+	mutex   sync.RWMutex
+	profile *DITProfile
+	regs    Registrations           // registrations lacking a parent registration
+	ants    Registrants             // registrants
+	entries map[string]*memoryEntry // all entries, keyed by normalized DN
+	order   []string                // normalized DNs, in order of addition
+}
+
+/*
+memoryEntry contains exactly one entry of a [MemoryDirectory].
+*/
+type memoryEntry struct {
+	reg   *Registration
+	ant   *Registrant
+	se    *Subentry
+	owner *Registration // registration of se, if any
+}
+
+/*
+NewMemoryDirectory returns a new instance of *[MemoryDirectory] alongside
+an error. The input *[DITProfile] is assigned to all entries subsequently
+added, and must be valid.
+
+All input registrations, their descendants and their subentries are added
+to the return instance, as are all input registrants. Each must bear a DN
+which is unique, and whose superior is a base of the profile or another
+such entry. The input instances are not copied, and may be modified by
+subsequent operations.
+*/
+func NewMemoryDirectory(profile *DITProfile, regs Registrations, ants Registrants) (dir *MemoryDirectory, err error) {
+	if !profile.Valid() {
+		err = DUAConfigValidityErr
+		return
+	}
+
+	r := &MemoryDirectory{
+		profile: profile,
+		entries: make(map[string]*memoryEntry),
+	}
+
+	for _, reg := range regs.flatten() {
+		if err = r.insert(&memoryEntry{reg: reg}); err != nil {
+			return
+		} else if reg.Parent().IsZero() && !r.regs.holds(reg) {
+			r.regs = append(r.regs, reg)
+		}
+
+		for i := 0; i < reg.Subentries().Len(); i++ {
+			if err = r.insert(&memoryEntry{se: reg.Subentries().Index(i), owner: reg}); err != nil {
+				return
+			}
+		}
+	}
+
+	for _, ant := range ants {
+		if err = r.insert(&memoryEntry{ant: ant}); err != nil {
+			return
+		}
+		r.ants = append(r.ants, ant)
+	}
+
+	for _, key := range r.order {
+		if dn := r.entries[key].dn(); !r.superiorExists(dn) {
+			err = wrapErr(NoSuchObjectErr, "superior of "+dn)
+			return
+		}
+	}
+
+	dir = r
+
+	return
+}
+
+/*
+Profile returns the *[DITProfile] instance in use by the receiver.
+*/
+func (r *MemoryDirectory) Profile() (prof *DITProfile) {
+	if r != nil {
+		prof = r.profile
+	}
+
+	return
+}
+
+/*
+Registrations returns the registrations of the receiver which lack a parent
+registration, each of which may be the root of a tree of registrations.
+*/
+func (r *MemoryDirectory) Registrations() (regs Registrations) {
+	if r != nil {
+		r.mutex.RLock()
+		defer r.mutex.RUnlock()
+		regs = append(regs, r.regs...)
+	}
+
+	return
+}
+
+/*
+Registrants returns the registrants of the receiver.
+*/
+func (r *MemoryDirectory) Registrants() (ants Registrants) {
+	if r != nil {
+		r.mutex.RLock()
+		defer r.mutex.RUnlock()
+		ants = append(ants, r.ants...)
+	}
+
+	return
+}
+
+/*
+Search returns all entries of the receiver which reside within the scope of
+the base DN and which match the input [RFC 4515] filter, in order of addition,
+alongside an error. The base may be any entry, or any base of the profile (or
+a superior thereof).
+
+If the input attribute types are zero, or include "*", all user types are
+returned. Operational types are returned if "+" is included, or if named.
+Collective values are returned only for the subentries which hold them; see
+[Registration.Effective] for a means of obtaining such values otherwise.
+
+Per [Section 3 of RFC 3672], subentries are only returned if the scope is
+BaseObjectScope, or if the filter asserts "(objectClass=subentry)".
+
+Attribute types and values are matched without regard for case. Ordering
+matches compare values numerically if both are integers, and approximate
+matches are treated as equality matches. Extensible matches are unsupported.
+
+[RFC 4515]: https://www.rfc-editor.org/rfc/rfc4515.html
+[Section 3 of RFC 3672]: https://www.rfc-editor.org/rfc/rfc3672.html#section-3
+*/
+func (r *MemoryDirectory) Search(base string, scope int, filter string, attrs []string) (entries []map[string][]string, err error) {
+	if r == nil {
+		err = NilInstanceErr
+		return
+	}
+
+	var f *searchFilter
+	if f, err = parseFilter(filter); err != nil {
+		return
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if !r.baseExists(base) {
+		err = wrapErr(NoSuchObjectErr, base)
+		return
+	}
+
+	subentries := scope == BaseObjectScope || f.asserts(`objectClass`, `subentry`)
+	brdns := rdnSequence(base)
+	for _, key := range r.order {
+		n := r.entries[key]
+		if n.se != nil && !subentries {
+			continue
+		}
+
+		rdns := rdnSequence(n.dn())
+		if !rdnSuffixEqual(rdns, brdns) {
+			continue
+		}
+
+		depth := len(rdns) - len(brdns)
+		switch scope {
+		case BaseObjectScope:
+			if depth != 0 {
+				continue
+			}
+		case SingleLevelScope:
+			if depth != 1 {
+				continue
+			}
+		}
+
+		if entry := n.entry(); f.match(entry) {
+			entries = append(entries, selectAttributes(entry, attrs))
+		}
+	}
+
+	return
+}
+
+/*
+Add creates the input entry within the receiver, returning an error if the
+entry already exists, if its superior does not exist or if its content is
+unsuitable.
+
+The nature of the entry is determined by its "objectClass" values, which
+must include "registration", "registrant" or "subentry". Registrants are
+only permitted under the terms of the "Dedicated Registrants Policy". The
+content of registrations and registrants is checked in the manner of
+[CheckEntrySchema], and the values of the RDN must be present.
+*/
+func (r *MemoryDirectory) Add(entry map[string][]string) (err error) {
+	if r == nil {
+		err = NilInstanceErr
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var dn string
+	if dns := entry[`dn`]; len(dns) > 0 {
+		dn = dns[0]
+	}
+
+	if len(rdnSequence(dn)) == 0 {
+		err = InvalidDNErr
+		return
+	} else if _, found := r.entries[normDN(dn)]; found {
+		err = wrapErr(EntryExistsErr, dn)
+		return
+	} else if !r.superiorExists(dn) {
+		err = wrapErr(NoSuchObjectErr, "superior of "+dn)
+		return
+	}
+
+	var n *memoryEntry
+	if n, err = r.build(copyEntry(entry)); err == nil {
+		r.attach(n)
+		err = r.insert(n)
+	}
+
+	return
+}
+
+/*
+Modify applies the input modifications to the entry bearing their DN, which
+must be the same for all, returning an error if any modification cannot be
+applied. Either all modifications are applied, or none are.
+
+Values are compared without regard for case. Adding a value already present,
+or deleting a value or type not present, is an error, as is the removal of
+a value of the RDN or a change of the nature of the entry.
+*/
+func (r *MemoryDirectory) Modify(mods ...Modification) (err error) {
+	if r == nil {
+		err = NilInstanceErr
+		return
+	} else if len(mods) == 0 {
+		err = NilArgumentsErr
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dn := mods[0].DN
+	n, found := r.entries[normDN(dn)]
+	if !found {
+		err = wrapErr(NoSuchObjectErr, dn)
+		return
+	}
+
+	entry := n.entry()
+	for _, mod := range mods {
+		if normDN(mod.DN) != normDN(dn) {
+			err = wrapErr(InvalidDNErr, "modifications of "+
+				dn+" and "+mod.DN+" cannot be combined")
+			return
+		} else if err = applyModification(entry, mod); err != nil {
+			return
+		}
+	}
+
+	for _, ava := range rdnValues(rdnSequence(dn)[0]) {
+		if !strInSlice(ava[1], entryValues(entry, ava[0])) {
+			err = wrapErr(NotAllowedOnRDNErr, ava[0])
+			return
+		}
+	}
+
+	var fresh *memoryEntry
+	if fresh, err = r.build(entry); err == nil {
+		err = n.update(fresh)
+	}
+
+	return
+}
+
+/*
+Delete removes the entry bearing the input DN from the receiver, returning
+an error if it is not found or has subordinates.
+
+If the entry is a registration, it is removed from the tree of its parent.
+Under the terms of the "Two Dimensional" model, any children it may have
+become parentless.
+*/
+func (r *MemoryDirectory) Delete(dn string) (err error) {
+	if r == nil {
+		err = NilInstanceErr
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := normDN(dn)
+	n, found := r.entries[key]
+	if !found {
+		err = wrapErr(NoSuchObjectErr, dn)
+		return
+	} else if r.hasSubordinates(dn) {
+		err = wrapErr(NotAllowedOnNonLeafErr, dn)
+		return
+	}
+
+	r.detach(n)
+	if n.reg != nil {
+		for _, child := range *n.reg.Children() {
+			child.r_Parent = nil
+			r.regs = append(r.regs, child)
+		}
+		n.reg.r_Children = nil
+	}
+
+	delete(r.entries, key)
+	for i, k := range r.order {
+		if k == key {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+
+	return
+}
+
+/*
+ModifyDN assigns the input RDN to the entry bearing the input DN, returning
+an error if the entry is not found, or if the new DN is taken or lacks an
+existing superior. If the new superior is non-zero, the entry is relocated
+beneath it.
+
+The values of the new RDN are added to the entry if absent, while those of
+the old RDN are removed if deleteOldRDN is true. The DN of every subordinate
+entry is rewritten likewise, and a relocated registration is linked into the
+tree of its new parent, if any.
+*/
+func (r *MemoryDirectory) ModifyDN(dn, newRDN string, deleteOldRDN bool, newSuperior string) (err error) {
+	if r == nil {
+		err = NilInstanceErr
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	n, found := r.entries[normDN(dn)]
+	if !found {
+		err = wrapErr(NoSuchObjectErr, dn)
+		return
+	} else if len(rdnSequence(newRDN)) != 1 {
+		err = wrapErr(InvalidDNErr, "bad RDN '"+newRDN+"'")
+		return
+	}
+
+	rdns := rdnSequence(dn)
+	sup := join(rdns[1:], `,`)
+	if len(newSuperior) > 0 {
+		sup = newSuperior
+	}
+
+	newDN := trimS(newRDN)
+	if len(sup) > 0 {
+		newDN += `,` + sup
+	}
+
+	if normDN(newDN) != normDN(dn) {
+		if _, taken := r.entries[normDN(newDN)]; taken {
+			err = wrapErr(EntryExistsErr, newDN)
+			return
+		} else if rdnSuffixEqual(rdnSequence(sup), rdns) {
+			err = wrapErr(InvalidDNErr, dn+" cannot be moved beneath itself")
+			return
+		}
+	}
+
+	if !r.superiorExists(newDN) {
+		err = wrapErr(NoSuchObjectErr, "superior of "+newDN)
+		return
+	}
+
+	entry := n.entry()
+	entry[`dn`] = []string{newDN}
+
+	add := rdnValues(trimS(newRDN))
+	for _, ava := range add {
+		if !strInSlice(ava[1], entryValues(entry, ava[0])) {
+			applyModification(entry, Modification{Operation: AddModification, Type: ava[0], Values: []string{ava[1]}})
+		}
+	}
+
+	if deleteOldRDN {
+		for _, ava := range rdnValues(rdns[0]) {
+			if !rdnAssertsValue(add, ava) {
+				applyModification(entry, Modification{Operation: DeleteModification, Type: ava[0], Values: []string{ava[1]}})
+			}
+		}
+	}
+
+	var fresh *memoryEntry
+	if fresh, err = r.build(entry); err != nil {
+		return
+	}
+
+	r.detach(n)
+	r.rename(dn, newDN)
+	if err = n.update(fresh); err == nil {
+		r.attach(n)
+	}
+
+	return
+}
+
+/*
+rename rewrites the DN of the entry bearing the old DN, as well as that of
+each of its subordinates, such that the old DN is replaced with the new DN.
+*/
+func (r *MemoryDirectory) rename(oldDN, newDN string) {
+	for i, key := range r.order {
+		n := r.entries[key]
+		if ndn, ok := rebaseDN(n.dn(), []string{oldDN}, newDN); ok {
+			n.setDN(ndn)
+			delete(r.entries, key)
+			r.order[i] = normDN(ndn)
+			r.entries[r.order[i]] = n
+		}
+	}
+}
+
+/*
+insert adds the input instance to the receiver's index, returning an error
+if its DN is malformed or taken by another entry. The insertion of an entry
+already present is ignored.
+*/
+func (r *MemoryDirectory) insert(n *memoryEntry) (err error) {
+	dn := n.dn()
+	if len(rdnSequence(dn)) == 0 {
+		err = wrapErr(InvalidDNErr, "'"+dn+"'")
+		return
+	}
+
+	key := normDN(dn)
+	if cur, found := r.entries[key]; found {
+		if cur.object() != n.object() {
+			err = wrapErr(EntryExistsErr, dn)
+		}
+		return
+	}
+
+	r.entries[key] = n
+	r.order = append(r.order, key)
+
+	return
+}
+
+/*
+build returns a new instance of *[memoryEntry] marshaled from the input
+entry, alongside an error.
+*/
+func (r *MemoryDirectory) build(entry map[string][]string) (n *memoryEntry, err error) {
+	dn := entryValues(entry, `dn`)
+	for _, ava := range rdnValues(rdnSequence(dn[0])[0]) {
+		if !strInSlice(ava[1], entryValues(entry, ava[0])) {
+			err = wrapErr(InvalidDNErr, "RDN value absent from "+dn[0])
+			return
+		}
+	}
+
+	meth := entryUnmarshaler(entry)
+	ocs := entryValues(entry, `objectClass`)
+
+	var violations []SchemaViolation
+	switch {
+	case strInSlice(`subentry`, ocs):
+		se := &Subentry{r_DITProfile: r.profile}
+		err = se.Marshal(meth)
+		n = &memoryEntry{se: se}
+	case strInSlice(`registrant`, ocs):
+		ant := &Registrant{R_DITProfile: r.profile}
+		if err = ant.Marshal(meth); err == nil {
+			if len(ant.R_SOC) == 0 {
+				ant.R_SOC = `registrant`
+			}
+			ant.refreshObjectClasses()
+			violations = ant.CheckSchema()
+		}
+		n = &memoryEntry{ant: ant}
+	case strInSlice(`registration`, ocs):
+		reg := &Registration{R_DITProfile: r.profile, r_root: new(registeredRoot)}
+		if err = reg.Marshal(meth); err == nil {
+			if soc := reg.expectedStructuralClass(); len(reg.R_SOC) == 0 && len(soc) > 0 {
+				reg.R_SOC = soc[0]
+			}
+			violations = reg.CheckSchema()
+		}
+		n = &memoryEntry{reg: reg}
+	default:
+		err = wrapErr(ObjectClassViolationErr, "registration, registrant or subentry class required")
+	}
+
+	if err == nil && len(violations) > 0 {
+		err = wrapErr(ObjectClassViolationErr, violations[0].String())
+	}
+
+	return
+}
+
+/*
+attach links the input instance into the registration tree, registrants or
+subentries of the receiver, as appropriate.
+*/
+func (r *MemoryDirectory) attach(n *memoryEntry) {
+	switch {
+	case n.reg != nil:
+		if parent := r.treeParent(n.reg); parent != nil {
+			n.reg.r_Parent = parent
+			*parent.Children() = append(*parent.Children(), n.reg)
+		} else {
+			r.regs = append(r.regs, n.reg)
+		}
+	case n.ant != nil:
+		r.ants = append(r.ants, n.ant)
+	default:
+		if sup := r.entries[normDN(superiorDN(n.dn()))]; sup != nil && sup.reg != nil {
+			n.owner = sup.reg
+			*sup.reg.Subentries() = append(*sup.reg.Subentries(), n.se)
+		}
+	}
+}
+
+/*
+detach unlinks the input instance from the registration tree, registrants
+or subentries of the receiver, as appropriate.
+*/
+func (r *MemoryDirectory) detach(n *memoryEntry) {
+	switch {
+	case n.reg != nil:
+		if parent := n.reg.r_Parent; parent != nil {
+			*parent.Children() = parent.Children().without(n.reg)
+			n.reg.r_Parent = nil
+		} else {
+			r.regs = r.regs.without(n.reg)
+		}
+	case n.ant != nil:
+		for i, ant := range r.ants {
+			if ant == n.ant {
+				r.ants = append(r.ants[:i:i], r.ants[i+1:]...)
+				break
+			}
+		}
+	case n.owner != nil:
+		ses := n.owner.Subentries()
+		for i, se := range *ses {
+			if se == n.se {
+				*ses = append((*ses)[:i:i], (*ses)[i+1:]...)
+				break
+			}
+		}
+		n.owner = nil
+	}
+}
+
+/*
+treeParent returns the registration which is the parent of the input
+registration, being that which is its superior entry or, failing that,
+that which bears the parent dotNotation value.
+*/
+func (r *MemoryDirectory) treeParent(reg *Registration) (parent *Registration) {
+	if sup := r.entries[normDN(superiorDN(reg.DN()))]; sup != nil && sup.reg != nil {
+		parent = sup.reg
+		return
+	}
+
+	arcs := dotSplit(reg.X680().DotNotation())
+	if len(arcs) < 2 {
+		return
+	}
+
+	dot := dotJoin(arcs[:len(arcs)-1])
+	for _, key := range r.order {
+		if n := r.entries[key]; n.reg != nil && n.reg != reg && n.reg.X680().DotNotation() == dot {
+			parent = n.reg
+			break
+		}
+	}
+
+	return
+}
+
+/*
+superiorExists returns a Boolean value indicative of whether the superior
+of the input DN is an entry of the receiver, or a base of its profile.
+*/
+func (r *MemoryDirectory) superiorExists(dn string) (ok bool) {
+	sup := normDN(superiorDN(dn))
+	if _, ok = r.entries[sup]; !ok {
+		for _, base := range append(append([]string{}, r.profile.R_RegBase...), r.profile.R_AthyBase...) {
+			if ok = normDN(base) == sup; ok {
+				break
+			}
+		}
+	}
+
+	return
+}
+
+/*
+baseExists returns a Boolean value indicative of whether the input DN is
+an entry of the receiver, or a base of its profile or a superior thereof.
+*/
+func (r *MemoryDirectory) baseExists(dn string) (ok bool) {
+	if _, ok = r.entries[normDN(dn)]; !ok {
+		rdns := rdnSequence(dn)
+		for _, base := range append(append([]string{}, r.profile.R_RegBase...), r.profile.R_AthyBase...) {
+			if ok = rdnSuffixEqual(rdnSequence(base), rdns); ok {
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func (r *MemoryDirectory) hasSubordinates(dn string) (has bool) {
+	rdns := rdnSequence(dn)
+	for _, key := range r.order {
+		sub := rdnSequence(r.entries[key].dn())
+		if has = len(sub) > len(rdns) && rdnSuffixEqual(sub, rdns); has {
+			break
+		}
+	}
+
+	return
+}
+
+func (r *memoryEntry) object() (obj any) {
+	switch {
+	case r.reg != nil:
+		obj = r.reg
+	case r.ant != nil:
+		obj = r.ant
+	default:
+		obj = r.se
+	}
+
+	return
+}
+
+func (r *memoryEntry) dn() (dn string) {
+	switch {
+	case r.reg != nil:
+		dn = r.reg.DN()
+	case r.ant != nil:
+		dn = r.ant.DN()
+	default:
+		dn = r.se.DN()
+	}
+
+	return
+}
+
+func (r *memoryEntry) setDN(dn string) {
+	switch {
+	case r.reg != nil:
+		r.reg.R_DN = dn
+	case r.ant != nil:
+		r.ant.R_DN = dn
+	default:
+		r.se.R_DN = dn
+	}
+}
+
+/*
+entry returns a copy of the content of the receiver, including any collective
+values held by a subentry.
+*/
+func (r *memoryEntry) entry() (entry map[string][]string) {
+	switch {
+	case r.reg != nil:
+		entry = r.reg.Unmarshal()
+	case r.ant != nil:
+		entry = r.ant.Unmarshal()
+	default:
+		entry = r.se.Unmarshal()
+		for _, x := range []any{r.se, r.se.R_X660, r.se.R_Spatial} {
+			for _, tag := range getAttributeTypeFieldTags(x) {
+				if isCollectiveTag(tag) {
+					if vals := readFieldByTag(tag, x); len(vals) > 0 {
+						entry[split(tag, `;`)[0]] = vals
+					}
+				}
+			}
+		}
+	}
+
+	return copyEntry(entry)
+}
+
+/*
+update overwrites the content of the receiver with that of the input instance
+of the same nature, while retaining its place within the registration tree.
+*/
+func (r *memoryEntry) update(fresh *memoryEntry) (err error) {
+	switch {
+	case r.reg != nil && fresh.reg != nil:
+		fresh.reg.r_Parent = r.reg.r_Parent
+		fresh.reg.r_Children = r.reg.r_Children
+		fresh.reg.r_se = r.reg.r_se
+		*r.reg = *fresh.reg
+	case r.ant != nil && fresh.ant != nil:
+		*r.ant = *fresh.ant
+	case r.se != nil && fresh.se != nil:
+		*r.se = *fresh.se
+	default:
+		err = wrapErr(ObjectClassViolationErr, "nature of "+r.dn()+" cannot be changed")
+	}
+
+	return
+}
+
+/*
+without returns the receiver sans the input instance, leaving the receiver
+itself undisturbed.
+*/
+func (r Registrations) without(reg *Registration) (regs Registrations) {
+	regs = make(Registrations, 0, len(r))
+	for _, x := range r {
+		if x != reg {
+			regs = append(regs, x)
+		}
+	}
+
+	return
+}
+
+func (r Registrations) holds(reg *Registration) (has bool) {
+	for i := 0; i < len(r) && !has; i++ {
+		has = r[i] == reg
+	}
+
+	return
+}
+
+/*
+applyModification applies the input [Modification] to the input entry.
+*/
+func applyModification(entry map[string][]string, mod Modification) (err error) {
+	at := mod.Type
+	for k := range entry {
+		if eq(k, at) {
+			at = k
+			break
+		}
+	}
+
+	if eq(at, `dn`) {
+		err = wrapErr(NotAllowedOnRDNErr, "dn")
+		return
+	}
+
+	cur, found := entry[at]
+	switch mod.Operation {
+	case AddModification:
+		for _, val := range mod.Values {
+			if strInSlice(val, cur) {
+				err = wrapErr(AttributeOrValueExistsErr, at+": "+val)
+				return
+			}
+			cur = append(cur, val)
+		}
+	case DeleteModification:
+		if !found {
+			err = wrapErr(NoSuchAttributeErr, at)
+			return
+		} else if len(mod.Values) == 0 {
+			cur = nil
+		}
+
+		for _, val := range mod.Values {
+			if !strInSlice(val, cur) {
+				err = wrapErr(NoSuchAttributeErr, at+": "+val)
+				return
+			}
+			cur = removeStrInSlice(val, cur)
+		}
+	case ReplaceModification:
+		cur = append([]string{}, mod.Values...)
+	default:
+		err = wrapErr(InvalidModificationErr, itoa(int(mod.Operation)))
+		return
+	}
+
+	if len(cur) == 0 {
+		delete(entry, at)
+	} else {
+		entry[at] = cur
+	}
+
+	return
+}
+
+/*
+selectAttributes returns a copy of the input entry which bears only those
+attribute types requested. See [MemoryDirectory.Search].
+*/
+func selectAttributes(entry map[string][]string, attrs []string) (out map[string][]string) {
+	user := len(attrs) == 0 || strInSlice(`*`, attrs)
+	oper := strInSlice(`+`, attrs)
+
+	out = map[string][]string{`dn`: entry[`dn`]}
+	for at, vals := range entry {
+		if at == `dn` {
+			continue
+		}
+
+		op := strInSlice(at, operationalTypes)
+		if (op && oper) || (!op && user) || strInSlice(at, attrs) {
+			out[at] = vals
+		}
+	}
+
+	return
+}
+
+/*
+entryUnmarshaler returns a function which writes the values of the input
+entry into the fields of an instance bearing the corresponding "ldap" tag,
+in the manner of [go-ldap/v3 Entry.Unmarshal]. Neither case nor the
+";collective" tag option is significant.
+
+[go-ldap/v3 Entry.Unmarshal]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#Entry.Unmarshal
+*/
+func entryUnmarshaler(entry map[string][]string) func(any) error {
+	return func(x any) (err error) {
+		for _, tag := range getAttributeTypeFieldTags(x) {
+			if vals := entryValues(entry, tag); len(vals) > 0 {
+				writeTagValues(x, tag, vals)
+			}
+		}
+
+		return
+	}
+}
+
+/*
+rdnValues returns the attribute type and value pairs of the input RDN.
+*/
+func rdnValues(rdn string) (avas [][2]string) {
+	for _, ava := range splitUnescaped(rdn, `+`, `\`) {
+		if i := idxr(ava, '='); i > 0 {
+			avas = append(avas, [2]string{trimS(ava[:i]), trimS(ava[i+1:])})
+		}
+	}
+
+	return
+}
+
+func rdnAssertsValue(avas [][2]string, ava [2]string) (ok bool) {
+	for i := 0; i < len(avas) && !ok; i++ {
+		ok = eq(avas[i][0], ava[0]) && eq(avas[i][1], ava[1])
+	}
+
+	return
+}
+
+func superiorDN(dn string) (sup string) {
+	if rdns := rdnSequence(dn); len(rdns) > 1 {
+		sup = join(rdns[1:], `,`)
+	}
+
+	return
+}
+
+func copyEntry(entry map[string][]string) (out map[string][]string) {
+	out = make(map[string][]string, len(entry))
+	for k, v := range entry {
+		out[k] = append([]string{}, v...)
+	}
+
+	return
+}
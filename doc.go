@@ -138,6 +138,8 @@ Specifically, this produces an abstraction of directional movement in the follow
   - "[rightArc]" - Right (nearest sibling numbered greater than the target)
   - "[subArc]" - Down (child registration(s))
 
+Each of these directions is available as a method of *[Registration] -- [Registration.Up], [Registration.Top], [Registration.First], [Registration.Last], [Registration.Left], [Registration.Right] and [Registration.Down] -- which resolve the relevant DN(s) to *[Registration] instances present within the loaded tree. For entries not yet loaded, an optional [GetOrSetFunc] loader may be supplied to fetch the instance by other means, such as an LDAP Search.
+
 Non-collective *[Spatial] attribute types may be set manually, or they may be present within entries marshaled into [Registration] instances as literal or collective values. Collective values are not meant for manual assignment, thus no related "set" methods exist in that regard.
 
 Like virtually all other methods in this package, the relevant *[Spatial] methods allow for [GetOrSetFunc] closure use, thereby letting the user enhance the behavior of instances of this type in a variety of ways:
@@ -0,0 +1,356 @@
+package radir
+
+/*
+dup.go contains Registrant duplicate detection and merging.
+*/
+
+import "reflect"
+
+/*
+RegistrantMatch describes the similarity of two *[Registrant] instances,
+as reported by [Registrants.Duplicates].
+*/
+type RegistrantMatch struct {
+	A, B  *Registrant
+	Score float64 // 0.0 (no similarity) through 1.0 (identical)
+}
+
+/*
+identityWeights contains the weights of those authority fields deemed to
+be most identifying. All other fields are weighed as one (1).
+*/
+var identityWeights map[string]float64 = map[string]float64{
+	`R_CN`:    3,
+	`R_O`:     3,
+	`R_Email`: 3,
+}
+
+/*
+identityWeight returns the weight of the named authority field.
+*/
+func identityWeight(name string) (weight float64) {
+	if weight = identityWeights[name]; weight == 0 {
+		weight = 1
+	}
+
+	return
+}
+
+/*
+Similarity returns a score between 0.0 and 1.0 describing the similarity of
+the receiver and the input *[Registrant] instance.
+
+Values of the *[FirstAuthority], *[CurrentAuthority] and *[Sponsor] instances
+of each registrant are correlated irrespective of role and attribute type
+strategy. Values are normalized prior to comparison, such that differences
+in case and whitespace are not significant. Telephone, facsimile and mobile
+numbers are compared by digits alone.
+
+Each field populated within either registrant is compared, thus a value
+present within one registrant but absent from the other (e.g.: a missing
+telephone number) is scored as a mismatch. Common names, organization names
+and email addresses bear greater weight than other fields. Start and end
+timestamps are not compared.
+
+A score of zero is returned if either registrant is nil, or if no fields
+are comparable.
+*/
+func (r *Registrant) Similarity(other *Registrant) (score float64) {
+	if r.IsZero() || other.IsZero() {
+		return
+	}
+
+	a, b := r.identity(), other.identity()
+
+	var matched, compared float64
+	for name, avals := range a {
+		weight := identityWeight(name)
+		compared += weight
+		for _, v := range avals {
+			if strInSlice(v, b[name]) {
+				matched += weight
+				break
+			}
+		}
+	}
+
+	// Fields populated only within the other
+	// registrant are scored as mismatches.
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			compared += identityWeight(name)
+		}
+	}
+
+	if compared > 0 {
+		score = matched / compared
+	}
+
+	return
+}
+
+/*
+identity returns the normalized authority values of the receiver, keyed
+by role-agnostic field name.
+*/
+func (r *Registrant) identity() (id map[string][]string) {
+	id = make(map[string][]string)
+	collect := func(name string, vals []string) {
+		if name == `R_StartTime` || name == `R_EndTime` {
+			return
+		}
+		for _, v := range vals {
+			if v = normalizeIdentity(name, v); len(v) > 0 && !strInSlice(v, id[name]) {
+				id[name] = append(id[name], v)
+			}
+		}
+	}
+
+	if !r.R_FA.IsZero() {
+		eachAuthorityValue(r.R_FA, collect)
+	}
+	if !r.R_CA.IsZero() {
+		eachAuthorityValue(r.R_CA, collect)
+	}
+	if !r.R_SA.IsZero() {
+		eachAuthorityValue(r.R_SA, collect)
+	}
+
+	return
+}
+
+func normalizeIdentity(name, v string) string {
+	switch name {
+	case `R_Tel`, `R_Fax`, `R_Mobile`:
+		bld := newBuilder()
+		for _, ch := range v {
+			if isDigit(ch) {
+				bld.WriteRune(ch)
+			}
+		}
+		v = bld.String()
+	default:
+		v = lc(condenseWHSP(v))
+	}
+
+	return v
+}
+
+/*
+Duplicates returns slices of [RegistrantMatch], each describing a pair of
+*[Registrant] instances within the receiver whose [Registrant.Similarity]
+score is greater than or equal to the input threshold. Matches are ordered
+by descending score.
+*/
+func (r Registrants) Duplicates(threshold float64) (matches []RegistrantMatch) {
+	for i := 0; i < len(r); i++ {
+		for j := i + 1; j < len(r); j++ {
+			if score := r[i].Similarity(r[j]); score > 0 && score >= threshold {
+				matches = append(matches, RegistrantMatch{A: r[i], B: r[j], Score: score})
+			}
+		}
+	}
+
+	stabSort(registrantMatches(matches))
+
+	return
+}
+
+/*
+registrantMatches implements [sort.Interface] for descending score order of
+[RegistrantMatch] instances.
+*/
+type registrantMatches []RegistrantMatch
+
+func (r registrantMatches) Len() int           { return len(r) }
+func (r registrantMatches) Less(i, j int) bool { return r[i].Score > r[j].Score }
+func (r registrantMatches) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+/*
+Merge consolidates the two input *[Registrant] instances, both of which must
+be present within the receiver, into a single survivor, which is returned.
+
+The survivor is the instance bearing the greater number of values, or a if
+the number is equal. Values of the other instance are assigned to the survivor
+where the corresponding survivor field is unset, while multi-valued fields are
+combined. Conflicting single values of the survivor are retained.
+
+The other instance is removed from the receiver, and all "[firstAuthority]",
+"[currentAuthority]" and "[sponsor]" DN values -- including collective forms
+held by subentries -- referencing the other instance throughout the input
+*[Registration] trees are rewritten to reference the survivor.
+
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+*/
+func (r *Registrants) Merge(regs Registrations, a, b *Registrant) (survivor *Registrant, err error) {
+	if a.IsZero() || b.IsZero() {
+		err = NilRegistrantErr
+		return
+	} else if a == b || eq(a.DN(), b.DN()) {
+		err = wrapErr(RegistrantValidityErr, "cannot merge a registrant with itself")
+		return
+	}
+
+	ai, bi := -1, -1
+	for i := 0; i < r.Len(); i++ {
+		switch r.Index(i) {
+		case a:
+			ai = i
+		case b:
+			bi = i
+		}
+	}
+
+	if ai == -1 || bi == -1 {
+		err = wrapErr(RegistrantValidityErr, "registrant not present")
+		return
+	}
+
+	survivor, loser, li := a, b, bi
+	if b.completeness() > a.completeness() {
+		survivor, loser, li = b, a, ai
+	}
+
+	survivor.absorb(loser)
+	*r = append((*r)[:li], (*r)[li+1:]...)
+
+	for _, reg := range regs.flatten() {
+		reg.R_X660.rewriteAuthorityDN(loser.DN(), survivor.DN())
+		if subs := reg.r_se; subs != nil {
+			for _, se := range *subs {
+				se.R_X660.rewriteAuthorityDN(loser.DN(), survivor.DN())
+			}
+		}
+	}
+
+	return
+}
+
+/*
+completeness returns the number of values held by the receiver, excluding
+its DN, object classes and other operational values.
+*/
+func (r *Registrant) completeness() (n int) {
+	count := func(name string, vals []string) {
+		switch name {
+		case `R_DN`, `R_OC`, `R_SOC`, `R_GSR`, `R_CAS`:
+			return
+		}
+		n += len(vals)
+	}
+	eachAuthorityValue(r, count)
+	for _, auth := range []any{r.R_FA, r.R_CA, r.R_SA} {
+		eachAuthorityValue(auth, count)
+	}
+
+	return
+}
+
+/*
+absorb assigns the values of the input instance to the receiver where
+unset, and combines multi-valued fields.
+*/
+func (r *Registrant) absorb(other *Registrant) {
+	absorbFields(r, other)
+
+	if !other.R_FA.IsZero() {
+		if r.R_FA.IsZero() {
+			r.R_FA = new(FirstAuthority)
+			r.R_FA.r_alt_types = other.R_FA.r_alt_types
+		}
+		absorbFields(r.R_FA, other.R_FA)
+	}
+
+	if !other.R_CA.IsZero() {
+		if r.R_CA.IsZero() {
+			r.R_CA = new(CurrentAuthority)
+			r.R_CA.r_alt_types = other.R_CA.r_alt_types
+		}
+		absorbFields(r.R_CA, other.R_CA)
+	}
+
+	if !other.R_SA.IsZero() {
+		if r.R_SA.IsZero() {
+			r.R_SA = new(Sponsor)
+			r.R_SA.r_alt_types = other.R_SA.r_alt_types
+		}
+		absorbFields(r.R_SA, other.R_SA)
+	}
+
+	r.refreshObjectClasses()
+}
+
+/*
+absorbFields assigns each exported string and []string field of src to
+the corresponding field of dst (both being pointers to the same struct
+type) where unset, and combines []string fields. Identity-bearing fields,
+such as DN and registrantID, are not absorbed into a populated dst.
+*/
+func absorbFields(dst, src any) {
+	dt, dv, ok := getReflectInstances(dst)
+	if !ok {
+		return
+	}
+	_, sv, _ := getReflectInstances(src)
+
+	for i := 0; i < dt.NumField(); i++ {
+		f := dt.Field(i)
+		tag := f.Tag.Get(`ldap`)
+		if !f.IsExported() || tag == `` || tag == `dn` ||
+			tag == `registrantID` || tag == `objectClass` {
+			continue
+		}
+
+		d, s := dv.Field(i), sv.Field(i)
+		switch d.Kind() {
+		case reflect.String:
+			if d.String() == `` {
+				d.SetString(s.String())
+			}
+		case reflect.Slice:
+			merged, _ := d.Interface().([]string)
+			vals, _ := s.Interface().([]string)
+			for _, v := range vals {
+				if !strInSlice(v, merged) {
+					merged = append(merged, v)
+				}
+			}
+			if len(merged) > 0 {
+				d.Set(valOf(merged))
+			}
+		}
+	}
+}
+
+/*
+rewriteAuthorityDN replaces all literal and collective authority DN values
+of the receiver which match old with repl. Case is not significant in the
+matching process, and duplicates resulting from the rewrite are removed.
+*/
+func (r *X660) rewriteAuthorityDN(old, repl string) {
+	if r.IsZero() {
+		return
+	}
+
+	for _, dns := range []*[]string{
+		&r.R_FAuthyDN, &r.R_CAuthyDN, &r.R_SAuthyDN,
+		&r.RC_FAuthyDN, &r.RC_CAuthyDN, &r.RC_SAuthyDN,
+	} {
+		if !strInSlice(old, *dns) {
+			continue
+		}
+
+		var out []string
+		for _, dn := range *dns {
+			if eq(dn, old) {
+				dn = repl
+			}
+			if !strInSlice(dn, out) {
+				out = append(out, dn)
+			}
+		}
+		*dns = out
+	}
+}
@@ -2,6 +2,7 @@ package radir
 
 import (
 	"errors"
+	"fmt"
 )
 
 /*
@@ -23,9 +24,31 @@ var (
 	NilGetOrSetFuncErr,
 	IllegalLongArcErr,
 	MismatchedLeafErr,
+	NoSubordinatesErr,
+	SubtreeSpecificationErr,
+	MissingObjectClassesErr,
+	StructureRuleErr,
+	NameFormErr,
+	InvalidTelephoneErr,
+	InvalidCountryErr,
+	InvalidStatusErr,
+	InvalidMailErr,
+	InvalidURIErr,
 	NilRegistrantErr,
 	InvalidGTFracErr,
+	InvalidSettingErr,
 	NilArgumentsErr,
+	InvalidTTLErr,
+	InvalidIdentifierErr,
+	InvalidFilterErr,
+	NoSuchObjectErr,
+	EntryExistsErr,
+	NotAllowedOnNonLeafErr,
+	NotAllowedOnRDNErr,
+	NoSuchAttributeErr,
+	InvalidModificationErr,
+	AttributeOrValueExistsErr,
+	ObjectClassViolationErr,
 	FrozenCacheErr,
 	NilInstanceErr,
 	IllegalRootErr,
@@ -53,8 +76,30 @@ func init() {
 	NilGetOrSetFuncErr = errors.New("GetOrSetFunc instance is nil")
 	IllegalLongArcErr = errors.New("LongArc cannot be applied to this registration type or root")
 	MismatchedLeafErr = errors.New("Mismatched NumberForm with leaf node of ASN.1 and/or DotNotation")
+	MissingObjectClassesErr = errors.New("objectClass values are required to evaluate a specificationFilter")
+	SubtreeSpecificationErr = errors.New("Subtree specification is malformed or contradictory")
+	StructureRuleErr = errors.New("Entry placement is not permitted by any DIT structure rule")
+	NameFormErr = errors.New("RDN does not conform to any permitted name form")
+	InvalidTelephoneErr = errors.New("Invalid ITU-T Rec. E.123 telephone number")
+	InvalidCountryErr = errors.New("Unassigned ISO 3166-1 country code or unknown country name")
+	InvalidStatusErr = errors.New("Invalid RFC 2578 status; must be current, deprecated or obsolete")
+	InvalidMailErr = errors.New("Invalid RFC 5322 mail address")
+	InvalidURIErr = errors.New("Invalid or relative URI")
+	NoSubordinatesErr = errors.New("Registration instance has no subordinate registrations")
 	NilRegistrantErr = errors.New("Registrant instance is nil")
+	InvalidSettingErr = errors.New("Setting is malformed, of the wrong type or poorly defined")
 	NilArgumentsErr = errors.New("Missing input arguments")
+	InvalidTTLErr = errors.New("TTL value is malformed or negative; must be a number of seconds")
+	InvalidIdentifierErr = errors.New("Identifier, IRI, unicodeValue or registrantID value is malformed or zero length")
+	InvalidFilterErr = errors.New("Search filter is malformed or unsupported")
+	NoSuchObjectErr = errors.New("No entry exists by the specified DN")
+	EntryExistsErr = errors.New("An entry already exists by the specified DN")
+	NotAllowedOnNonLeafErr = errors.New("Operation not allowed upon an entry which has subordinates")
+	NotAllowedOnRDNErr = errors.New("Operation would remove a value of the entry's RDN")
+	NoSuchAttributeErr = errors.New("Attribute type or value is not present within the entry")
+	InvalidModificationErr = errors.New("Unknown modification operation")
+	AttributeOrValueExistsErr = errors.New("Attribute value is already present within the entry")
+	ObjectClassViolationErr = errors.New("Entry content violates its object class definitions")
 	FrozenCacheErr = errors.New("Cache is frozen")
 	NilInstanceErr = errors.New("Instance is nil")
 	IllegalRootErr = errors.New("Illegal root; must be 'name' or 'name(0|1|2)' or 0|1|2")
@@ -68,6 +113,14 @@ func init() {
 		": Fraction exceeds Generalized Time fractional limit")
 }
 
+/*
+wrapErr returns an error bearing the input detail, which [errors.Is] reports
+as matching the input error.
+*/
+func wrapErr(err error, detail string) error {
+	return fmt.Errorf("%w: %s", err, detail)
+}
+
 func errorf(msg any, x ...any) error {
 	switch tv := msg.(type) {
 	case string:
@@ -69,6 +69,8 @@ type FirstAuthority struct {
 	R_URI_alt    []string `ldap:"labeledURI"`               // RFC 2079 § 2
 
 	r_alt_types bool
+	r_syntax    bool
+	r_reg       *Registration // owning registration ("Combined Registrants Policy" only)
 }
 
 /*
@@ -655,6 +657,19 @@ func (r *FirstAuthority) StartTimeGetFunc(getfunc GetOrSetFunc) (any, error) {
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `firstAuthorityStartTimestamp`)
 }
 
+/*
+Start returns the [GeneralizedTime] form of the start time assigned to
+the receiver instance. A zero instance is returned if the value is unset
+or cannot be parsed.
+*/
+func (r *FirstAuthority) Start() (gt GeneralizedTime) {
+	if !r.IsZero() {
+		gt, _ = ParseGeneralizedTime(r.R_StartTime)
+	}
+
+	return
+}
+
 /*
 EndTime returns the string-based generalized time value that reflects the
 time at which the receiver was (or will be) terminated.
@@ -682,6 +697,19 @@ func (r *FirstAuthority) EndTimeGetFunc(getfunc GetOrSetFunc) (any, error) {
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `firstAuthorityEndTimestamp`)
 }
 
+/*
+End returns the [GeneralizedTime] form of the end time assigned to the
+receiver instance. A zero instance is returned if the value is unset or
+cannot be parsed.
+*/
+func (r *FirstAuthority) End() (gt GeneralizedTime) {
+	if !r.IsZero() {
+		gt, _ = ParseGeneralizedTime(r.R_EndTime)
+	}
+
+	return
+}
+
 /*
 Auxiliary returns the static string value "[firstAuthorityContext]" as a
 convenient means of determining the AUXILIARY class associated with an
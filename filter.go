@@ -0,0 +1,353 @@
+package radir
+
+/*
+filter.go contains a minimal RFC 4515 search filter parser and evaluator.
+*/
+
+import "strconv"
+
+/*
+filterKind describes the nature of a [searchFilter].
+*/
+type filterKind uint8
+
+const (
+	andFilter filterKind = iota
+	orFilter
+	notFilter
+	equalityFilter
+	substringsFilter
+	greaterOrEqualFilter
+	lessOrEqualFilter
+	presentFilter
+	approxMatchFilter
+)
+
+/*
+searchFilter is the parsed form of a string [RFC 4515] search filter.
+
+[RFC 4515]: https://www.rfc-editor.org/rfc/rfc4515.html
+*/
+type searchFilter struct {
+	kind  filterKind
+	at    string          // attribute description of an item
+	value string          // unescaped assertion value of an item
+	subs  []string        // unescaped initial, any and final substrings; initial and final may be zero
+	terms []*searchFilter // components of an AND, OR or NOT filter
+}
+
+/*
+parseFilter returns an instance of *[searchFilter] alongside an error
+following an attempt to parse the input [RFC 4515] string filter. A zero
+string is equivalent to "(objectClass=*)", and the outermost parentheses
+may be omitted.
+
+Extensible match filters are not supported.
+
+[RFC 4515]: https://www.rfc-editor.org/rfc/rfc4515.html
+*/
+func parseFilter(str string) (f *searchFilter, err error) {
+	if str = trimS(str); len(str) == 0 {
+		str = `(objectClass=*)`
+	} else if str[0] != '(' {
+		str = `(` + str + `)`
+	}
+
+	var rest string
+	if f, rest, err = parseFilterComponent(str); err == nil && len(trimS(rest)) > 0 {
+		err = wrapErr(InvalidFilterErr, "unexpected '"+rest+"'")
+	}
+
+	return
+}
+
+func parseFilterComponent(str string) (f *searchFilter, rest string, err error) {
+	if len(str) < 2 || str[0] != '(' {
+		err = wrapErr(InvalidFilterErr, "expected '(' in '"+str+"'")
+		return
+	}
+
+	f = new(searchFilter)
+	switch str[1] {
+	case '&', '|':
+		if f.kind = andFilter; str[1] == '|' {
+			f.kind = orFilter
+		}
+
+		for rest = trimS(str[2:]); len(rest) > 0 && rest[0] == '('; rest = trimS(rest) {
+			var term *searchFilter
+			if term, rest, err = parseFilterComponent(rest); err != nil {
+				return
+			}
+			f.terms = append(f.terms, term)
+		}
+	case '!':
+		f.kind = notFilter
+
+		var term *searchFilter
+		if term, rest, err = parseFilterComponent(trimS(str[2:])); err != nil {
+			return
+		}
+		f.terms = []*searchFilter{term}
+	default:
+		// a literal ')' must be escaped within an
+		// assertion value, thus the first one ends
+		// the item.
+		end := idxr(str, ')')
+		if end < 0 {
+			err = wrapErr(InvalidFilterErr, "unterminated item '"+str+"'")
+			return
+		} else if err = f.parseItem(str[1:end]); err != nil {
+			return
+		}
+		rest = str[end:]
+	}
+
+	if len(rest) == 0 || rest[0] != ')' {
+		err = wrapErr(InvalidFilterErr, "expected ')' in '"+str+"'")
+		return
+	}
+	rest = rest[1:]
+
+	return
+}
+
+/*
+parseItem parses the input simple, presence or substrings item, sans its
+enclosing parentheses, into the receiver instance.
+*/
+func (r *searchFilter) parseItem(item string) (err error) {
+	eqi := idxr(item, '=')
+	if eqi < 1 {
+		err = wrapErr(InvalidFilterErr, "malformed item '"+item+"'")
+		return
+	}
+
+	at, raw := item[:eqi], item[eqi+1:]
+	r.kind = equalityFilter
+	switch at[len(at)-1] {
+	case '>':
+		r.kind, at = greaterOrEqualFilter, at[:len(at)-1]
+	case '<':
+		r.kind, at = lessOrEqualFilter, at[:len(at)-1]
+	case '~':
+		r.kind, at = approxMatchFilter, at[:len(at)-1]
+	case ':':
+		err = wrapErr(InvalidFilterErr, "extensible match is not supported")
+		return
+	}
+
+	if !isFilterAttribute(at) {
+		err = wrapErr(InvalidFilterErr, "bad attribute description '"+at+"'")
+		return
+	}
+	r.at = at
+
+	if r.kind == equalityFilter && raw == `*` {
+		r.kind = presentFilter
+		return
+	} else if r.kind == equalityFilter && idxr(raw, '*') >= 0 {
+		r.kind = substringsFilter
+		for _, sub := range split(raw, `*`) {
+			var val string
+			if val, err = unescapeFilterValue(sub); err != nil {
+				return
+			}
+			r.subs = append(r.subs, val)
+		}
+		return
+	}
+
+	r.value, err = unescapeFilterValue(raw)
+
+	return
+}
+
+func isFilterAttribute(at string) (ok bool) {
+	if ok = len(at) > 0; ok {
+		for _, c := range at {
+			if !(isLetter(c) || isDigit(c) || c == '-' || c == '.' || c == ';') {
+				ok = false
+				break
+			}
+		}
+	}
+
+	return
+}
+
+/*
+unescapeFilterValue returns the input [RFC 4515] assertion value with all
+'\XX' hexadecimal escape sequences replaced with the bytes they represent.
+
+[RFC 4515]: https://www.rfc-editor.org/rfc/rfc4515.html#section-3
+*/
+func unescapeFilterValue(raw string) (val string, err error) {
+	if idxr(raw, '\\') < 0 {
+		val = raw
+		return
+	}
+
+	var out []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			out = append(out, raw[i])
+			continue
+		}
+
+		if i+2 >= len(raw) {
+			err = wrapErr(InvalidFilterErr, "truncated escape sequence in '"+raw+"'")
+			return
+		}
+
+		b, perr := strconv.ParseUint(raw[i+1:i+3], 16, 8)
+		if perr != nil {
+			err = wrapErr(InvalidFilterErr, "bad escape sequence in '"+raw+"'")
+			return
+		}
+		out = append(out, byte(b))
+		i += 2
+	}
+	val = string(out)
+
+	return
+}
+
+/*
+escapeFilterValue returns the input value with those characters which are
+significant within an [RFC 4515] assertion value escaped, namely '*', '(',
+')', '\' and NUL.
+
+[RFC 4515]: https://www.rfc-editor.org/rfc/rfc4515.html#section-3
+*/
+func escapeFilterValue(val string) string {
+	bld := newBuilder()
+	for i := 0; i < len(val); i++ {
+		switch c := val[i]; c {
+		case '*', '(', ')', '\\', 0:
+			bld.WriteString(sprintf("\\%02x", c))
+		default:
+			bld.WriteByte(c)
+		}
+	}
+
+	return bld.String()
+}
+
+/*
+match returns a Boolean value indicative of whether the input entry, of
+the form returned by the various Unmarshal methods, is matched by the
+receiver instance.
+
+Attribute descriptions and values are matched without regard for case.
+Ordering matches compare values numerically if both are integers, else
+lexically. Approximate matches are treated as equality matches. As every
+entry bears an objectClass, "(objectClass=*)" matches any entry.
+*/
+func (r *searchFilter) match(entry map[string][]string) (ok bool) {
+	switch r.kind {
+	case andFilter:
+		ok = true
+		for i := 0; i < len(r.terms) && ok; i++ {
+			ok = r.terms[i].match(entry)
+		}
+		return
+	case orFilter:
+		for i := 0; i < len(r.terms) && !ok; i++ {
+			ok = r.terms[i].match(entry)
+		}
+		return
+	case notFilter:
+		ok = !r.terms[0].match(entry)
+		return
+	case presentFilter:
+		if eq(r.at, `objectClass`) {
+			ok = true
+			return
+		}
+	}
+
+	vals := entryValues(entry, r.at)
+	for i := 0; i < len(vals) && !ok; i++ {
+		switch r.kind {
+		case presentFilter:
+			ok = true
+		case equalityFilter, approxMatchFilter:
+			ok = eq(vals[i], r.value)
+		case greaterOrEqualFilter:
+			ok = compareValues(vals[i], r.value) >= 0
+		case lessOrEqualFilter:
+			ok = compareValues(vals[i], r.value) <= 0
+		case substringsFilter:
+			ok = matchSubstrings(lc(vals[i]), r.subs)
+		}
+	}
+
+	return
+}
+
+/*
+asserts returns a Boolean value indicative of whether the receiver, or any
+of its components, is an equality match of the input type and value.
+*/
+func (r *searchFilter) asserts(at, value string) (ok bool) {
+	if ok = r.kind == equalityFilter && eq(r.at, at) && eq(r.value, value); !ok {
+		for i := 0; i < len(r.terms) && !ok; i++ {
+			ok = r.terms[i].asserts(at, value)
+		}
+	}
+
+	return
+}
+
+/*
+entryValues returns the values of the input attribute type present within
+the input entry. Case is not significant, nor is any attribute option.
+*/
+func entryValues(entry map[string][]string, at string) (vals []string) {
+	at = split(at, `;`)[0]
+	for k, v := range entry {
+		if eq(split(k, `;`)[0], at) {
+			vals = append(vals, v...)
+		}
+	}
+
+	return
+}
+
+func compareValues(a, b string) (cmp int) {
+	if x, ok := atobig(a); ok {
+		if y, ok := atobig(b); ok {
+			cmp = x.Cmp(y)
+			return
+		}
+	}
+
+	if a, b = lc(a), lc(b); a < b {
+		cmp = -1
+	} else if a > b {
+		cmp = 1
+	}
+
+	return
+}
+
+func matchSubstrings(val string, subs []string) (ok bool) {
+	last := len(subs) - 1
+	if !hasPfx(val, lc(subs[0])) {
+		return
+	}
+	val = val[len(subs[0]):]
+
+	for _, sub := range subs[1:last] {
+		i := idxs(val, lc(sub))
+		if i < 0 {
+			return
+		}
+		val = val[i+len(sub):]
+	}
+
+	ok = hasSfx(val, lc(subs[last]))
+
+	return
+}
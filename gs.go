@@ -273,62 +273,23 @@ func nanfToIdAndNF(in string) (id, n string) {
 
 /*
 gt2t marshals the input generalized time string value (in) into an
-instance of [time.Time] (out).
+instance of [time.Time] (out). See [ParseGeneralizedTime] for details.
 */
 func gt2t(in string) (out time.Time, err error) {
-	var (
-		format string = tfmt // base format
-		diff   string = `-0700`
-		base   string
-	)
-
-	if len(in) < 15 {
-		err = InvalidGTErr
-		return
-	}
-
-	if zulu := in[len(in)-1] == 'Z'; zulu {
-		in = in[:len(in)-1]
-	}
-
-	// If we've got nothing left, must be zulu
-	// without any fractional or differential
-	// components
-	if base = in[14:]; len(base) == 0 {
-		out, err = time.Parse(format, in)
-		return
+	var gt GeneralizedTime
+	if gt, err = ParseGeneralizedTime(in); err == nil {
+		out = gt.Time()
 	}
 
-	// Handle fractional component (up to six (6) digits)
-	if base[0] == '.' || base[0] == ',' {
-		format += string(".")
-		for fidx, ch := range base[1:] {
-			if fidx > 6 {
-				err = InvalidGTFracErr
-				return
-			} else if isDigit(ch) {
-				format += `0`
-			}
-		}
-	}
-
-	// Handle differential time, or bail out if not
-	// already known to be zulu.
-	if in[len(in)-5] == '+' || in[len(in)-5] == '-' {
-		format += diff
-	}
-
-	out, err = time.Parse(format, in)
-
 	return
 }
 
 /*
-t2gt marshals the input [time.Time] instance (in) into a generalized
-time string instance (out).
+t2gt marshals the input [time.Time] instance (in) into a UTC generalized
+time string instance (out) to the second.
 */
 func t2gt(in time.Time) (out string) {
-	out = in.Format(tfmt) + `Z`
+	out = GeneralizedTime(in).Format(time.Second, nil)
 	return
 }
 
@@ -157,6 +157,67 @@ func TestGS_codecov(t *testing.T) {
 		})
 }
 
+func TestGeneralizedTime(t *testing.T) {
+	for idx, valid := range []struct {
+		in, want string
+	}{
+		{`2008011415Z`, `20080114150000Z`},
+		{`200801141546Z`, `20080114154600Z`},
+		{`20080114154613Z`, `20080114154613Z`},
+		{`20080114154613.25Z`, `20080114154613.25Z`},
+		{`20080114154613,25Z`, `20080114154613.25Z`},
+		{`2008011415.5Z`, `20080114153000Z`},
+		{`200801141546,5Z`, `20080114154630Z`},
+		{`20080114154613-0600`, `20080114214613Z`},
+		{`20080114154613.5+01`, `20080114144613.5Z`},
+		{`20080114154613`, `20080114154613Z`},
+	} {
+		gt, err := ParseGeneralizedTime(valid.in)
+		if err != nil {
+			t.Errorf("%s[%d] failed: %v", t.Name(), idx, err)
+		} else if got := gt.String(); got != valid.want {
+			t.Errorf("%s[%d] failed:\nwant: %s\ngot:  %s", t.Name(), idx, valid.want, got)
+		}
+	}
+
+	for idx, bogus := range []string{
+		`200801141`, `20081314154613Z`, `2008011415.Z`, `20080114154613Q`,
+		`20080114154613+2500`, `20080114154613.1234567Z`,
+	} {
+		if _, err := ParseGeneralizedTime(bogus); err == nil {
+			t.Errorf("%s[%d] failed: expected error for %q", t.Name(), idx, bogus)
+		}
+	}
+
+	gt, _ := ParseGeneralizedTime(`20080114154613.25Z`)
+	if got := gt.Format(time.Minute, time.FixedZone(``, -6*3600)); got != `200801140946-0600` {
+		t.Errorf("%s failed: unexpected format %s", t.Name(), got)
+	} else if got = gt.Truncate(time.Hour).String(); got != `20080114150000Z` {
+		t.Errorf("%s failed: unexpected truncation %s", t.Name(), got)
+	} else if got = gt.Add(90 * time.Minute).String(); got != `20080114171613.25Z` {
+		t.Errorf("%s failed: unexpected addition %s", t.Name(), got)
+	} else if d := gt.Add(-time.Hour).Sub(gt); d != -time.Hour {
+		t.Errorf("%s failed: unexpected difference %s", t.Name(), d)
+	}
+
+	reg := myDedicatedProfile.NewRegistration()
+	reg.Supplement().SetModifyTime(`20100101000000Z`)
+	reg.Supplement().SetModifyTime(GeneralizedTime(gt.Time()))
+	reg.Supplement().SetModifyTime(time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC))
+	reg.Supplement().SetModifyTime(`bogus`)
+
+	mods := reg.Supplement().Modified()
+	if got := mods.Strings(); len(got) != 3 || got[0] != `20010101000000Z` || got[2] != `20100101000000Z` {
+		t.Errorf("%s failed: unexpected modification history %v", t.Name(), got)
+	} else if between := mods.Between(mods[0].Truncate(24*time.Hour), mods[1]); len(between) != 2 {
+		t.Errorf("%s failed: unexpected range %v", t.Name(), between.Strings())
+	} else if err := reg.Supplement().SetCreateTime(gt.Time()); err != nil || !reg.Supplement().Created().Equal(gt) {
+		t.Errorf("%s failed: creation time not assigned: %v", t.Name(), err)
+	} else if err = reg.X660().SetStdNameForm(gt); err != UnsupportedInputTypeErr {
+		t.Errorf("%s failed: expected UnsupportedInputTypeErr, got %v", t.Name(), err)
+	}
+}
+
 func TestTime_codecov(t *testing.T) {
 	var ts []any = []any{
 		`20010718155634-0600.019283Z`,
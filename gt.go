@@ -0,0 +1,361 @@
+package radir
+
+/*
+gt.go contains the GeneralizedTime type and its methods.
+*/
+
+import (
+	"sort"
+	"time"
+)
+
+/*
+GeneralizedTime implements the ASN.1 GeneralizedTime type, as defined in
+[Clause 46 of ITU-T Rec. X.680], and as used by "[registrationCreated]",
+"[registrationModified]" and all authority timestamp types.
+
+Instances of this type are usually obtained through [ParseGeneralizedTime],
+though any instance of [time.Time] may be converted directly.
+
+[Clause 46 of ITU-T Rec. X.680]: https://www.itu.int/rec/T-REC-X.680
+[registrationCreated]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.11
+[registrationModified]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.12
+*/
+type GeneralizedTime time.Time
+
+/*
+GeneralizedTimes implements a sortable slice type of [GeneralizedTime].
+*/
+type GeneralizedTimes []GeneralizedTime
+
+/*
+ParseGeneralizedTime returns an instance of [GeneralizedTime] alongside an
+error following an attempt to parse the input string value.
+
+All variants defined by X.680 are supported, namely:
+
+  - a date and hour ("YYYYMMDDHH"), optionally followed by minutes ("MM") and then seconds ("SS")
+  - an optional fraction of the least significant component, delimited by a period (.) or comma (,)
+  - an optional "Z" to indicate UTC, or a differential offset ("+HH", "-HH", "+HHMM" or "-HHMM")
+
+Values which bear neither "Z" nor a differential offset represent local
+time as defined by X.680, and are interpreted as UTC, as no other locale
+can be assumed. Fractions of more than six (6) digits are not supported.
+*/
+func ParseGeneralizedTime(in string) (gt GeneralizedTime, err error) {
+	var (
+		nums [6]int // year, month, day, hour, minute, second
+		last int    // index of least significant component
+		pos  int
+	)
+
+	err = InvalidGTErr
+	if len(in) < 10 {
+		return
+	}
+
+	digits := func(n int) (v int, ok bool) {
+		if pos+n > len(in) {
+			return
+		}
+		for _, ch := range in[pos : pos+n] {
+			if !isDigit(ch) {
+				return
+			}
+			v = v*10 + int(ch-'0')
+		}
+		pos += n
+		ok = true
+		return
+	}
+
+	var ok bool
+	if nums[0], ok = digits(4); !ok {
+		return
+	}
+	for last = 1; last < 6; last++ {
+		if pos == len(in) || !isDigit(rune(in[pos])) {
+			break
+		} else if nums[last], ok = digits(2); !ok {
+			return
+		}
+	}
+
+	if last--; last < 3 {
+		// date and hour are mandatory
+		return
+	}
+
+	var frac time.Duration
+	if pos < len(in) && (in[pos] == '.' || in[pos] == ',') {
+		pos++
+		start := pos
+		for pos < len(in) && isDigit(rune(in[pos])) {
+			pos++
+		}
+
+		if pos == start {
+			return
+		} else if pos-start > 6 {
+			err = InvalidGTFracErr
+			return
+		}
+
+		num, den := time.Duration(0), time.Duration(1)
+		for _, ch := range in[start:pos] {
+			num, den = num*10+time.Duration(ch-'0'), den*10
+		}
+		unit := []time.Duration{time.Hour, time.Minute, time.Second}[last-3]
+		frac = unit * num / den
+	}
+
+	loc := time.UTC
+	if pos < len(in) {
+		switch in[pos] {
+		case 'Z':
+			pos++
+		case '+', '-':
+			sign := 1
+			if in[pos] == '-' {
+				sign = -1
+			}
+			pos++
+
+			var hh, mm int
+			if hh, ok = digits(2); !ok || hh > 23 {
+				return
+			}
+			if pos < len(in) {
+				if mm, ok = digits(2); !ok || mm > 59 {
+					return
+				}
+			}
+			loc = time.FixedZone(``, sign*(hh*3600+mm*60))
+		}
+	}
+
+	if pos != len(in) {
+		return
+	}
+
+	t := time.Date(nums[0], time.Month(nums[1]), nums[2], nums[3], nums[4], nums[5], 0, loc)
+	if t.Year() != nums[0] || int(t.Month()) != nums[1] || t.Day() != nums[2] ||
+		t.Hour() != nums[3] || t.Minute() != nums[4] || t.Second() != nums[5] {
+		// out of range component(s), e.g.: month 13
+		return
+	}
+
+	gt = GeneralizedTime(t.Add(frac))
+	err = nil
+
+	return
+}
+
+/*
+Time returns the underlying [time.Time] instance.
+*/
+func (r GeneralizedTime) Time() time.Time {
+	return time.Time(r)
+}
+
+/*
+IsZero returns a Boolean value indicative of a zero receiver state.
+*/
+func (r GeneralizedTime) IsZero() bool {
+	return r.Time().IsZero()
+}
+
+/*
+String returns the canonical string representation of the receiver
+instance, which is expressed in UTC to the second, followed by any
+non-zero fraction of a second (e.g.: "20080114214613.25Z").
+
+A zero string is returned if the receiver is zero.
+*/
+func (r GeneralizedTime) String() (s string) {
+	if !r.IsZero() {
+		s = r.Format(time.Nanosecond, nil)
+	}
+
+	return
+}
+
+/*
+Format returns the string representation of the receiver instance at the
+input precision and location.
+
+A precision of [time.Hour] or [time.Minute] produces the "YYYYMMDDHH" or
+"YYYYMMDDHHMM" forms respectively, while [time.Second] produces the usual
+"YYYYMMDDHHMMSS" form. Finer precisions produce a fraction of a second of
+no more than six (6) digits, from which trailing zeros are removed.
+
+A nil or UTC location produces a trailing "Z", while any other location
+produces a differential offset (e.g.: "-0600").
+*/
+func (r GeneralizedTime) Format(precision time.Duration, loc *time.Location) (s string) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t := r.Time().In(loc)
+	switch {
+	case precision >= time.Hour:
+		s = t.Format(`2006010215`)
+	case precision >= time.Minute:
+		s = t.Format(`200601021504`)
+	case precision >= time.Second:
+		s = t.Format(tfmt)
+	default:
+		s = trimSfx(t.Format(tfmt+`.000000`), `.000000`)
+		for hasSfx(s, `0`) && idxr(s, '.') != -1 {
+			s = s[:len(s)-1]
+		}
+	}
+
+	if loc == time.UTC {
+		s += `Z`
+	} else {
+		s += t.Format(`-0700`)
+	}
+
+	return
+}
+
+/*
+Compare returns -1 if the receiver precedes the input instance, 1 if
+the receiver follows the input instance, or 0 if both are equal.
+*/
+func (r GeneralizedTime) Compare(gt GeneralizedTime) (c int) {
+	switch t := r.Time(); {
+	case t.Before(gt.Time()):
+		c = -1
+	case t.After(gt.Time()):
+		c = 1
+	}
+
+	return
+}
+
+/*
+Before returns a Boolean value indicative of whether the receiver precedes
+the input instance.
+*/
+func (r GeneralizedTime) Before(gt GeneralizedTime) bool {
+	return r.Compare(gt) < 0
+}
+
+/*
+After returns a Boolean value indicative of whether the receiver follows
+the input instance.
+*/
+func (r GeneralizedTime) After(gt GeneralizedTime) bool {
+	return r.Compare(gt) > 0
+}
+
+/*
+Equal returns a Boolean value indicative of whether the receiver and the
+input instance represent the same instant, regardless of location.
+*/
+func (r GeneralizedTime) Equal(gt GeneralizedTime) bool {
+	return r.Compare(gt) == 0
+}
+
+/*
+Truncate returns the receiver rounded down to a multiple of the input
+duration, as described by [time.Time.Truncate]. For example, truncation
+to [time.Hour] removes any minutes, seconds and fractions thereof.
+*/
+func (r GeneralizedTime) Truncate(d time.Duration) GeneralizedTime {
+	return GeneralizedTime(r.Time().Truncate(d))
+}
+
+/*
+Add returns the receiver offset by the input duration, as described by
+[time.Time.Add]. A negative duration produces an earlier instance.
+*/
+func (r GeneralizedTime) Add(d time.Duration) GeneralizedTime {
+	return GeneralizedTime(r.Time().Add(d))
+}
+
+/*
+Sub returns the duration elapsed between the input instance and the
+receiver, as described by [time.Time.Sub]. The result is negative if
+the receiver precedes the input instance.
+*/
+func (r GeneralizedTime) Sub(gt GeneralizedTime) time.Duration {
+	return r.Time().Sub(gt.Time())
+}
+
+/*
+Len returns the integer length of the receiver instance.
+*/
+func (r GeneralizedTimes) Len() int { return len(r) }
+
+/*
+Less returns a Boolean value indicative of whether the Ith instance
+precedes the Jth instance.
+*/
+func (r GeneralizedTimes) Less(i, j int) bool { return r[i].Before(r[j]) }
+
+/*
+Swap swaps the Ith and Jth instances.
+*/
+func (r GeneralizedTimes) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+
+/*
+Sort sorts the receiver instance chronologically. Equal instances retain
+their original order.
+*/
+func (r GeneralizedTimes) Sort() { sort.Stable(r) }
+
+/*
+Strings returns the canonical string representation of each instance
+within the receiver. See [GeneralizedTime.String] for details.
+*/
+func (r GeneralizedTimes) Strings() (s []string) {
+	for i := 0; i < len(r); i++ {
+		s = append(s, r[i].String())
+	}
+
+	return
+}
+
+/*
+Between returns the instances within the receiver which fall within the
+inclusive range of the input instances.
+*/
+func (r GeneralizedTimes) Between(lo, hi GeneralizedTime) (in GeneralizedTimes) {
+	for i := 0; i < len(r); i++ {
+		if !r[i].Before(lo) && !r[i].After(hi) {
+			in = append(in, r[i])
+		}
+	}
+
+	return
+}
+
+/*
+parseGeneralizedTimes returns the [GeneralizedTimes] instance parsed from
+the input string values, sorted chronologically. Values which cannot be
+parsed are discarded.
+*/
+func parseGeneralizedTimes(vals []string) (gts GeneralizedTimes) {
+	for i := 0; i < len(vals); i++ {
+		if gt, err := ParseGeneralizedTime(vals[i]); err == nil {
+			gts = append(gts, gt)
+		}
+	}
+	gts.Sort()
+
+	return
+}
+
+/*
+isGeneralizedTimeType returns a Boolean value indicative of whether the
+input attribute type bears generalized time values.
+*/
+func isGeneralizedTimeType(at string) bool {
+	at = lc(at)
+	return hasSfx(at, `timestamp`) || at == `registrationcreated` ||
+		at == `registrationmodified`
+}
@@ -56,6 +56,328 @@ const (
 	DefaultArcSearchItem          = `(objectClass=arc)`
 )
 
+/*
+Search scopes, each of which corresponds to the respective scope constant
+of [ldap/v3] (e.g.: SingleLevelScope is equivalent to [ldap/v3.ScopeSingleLevel]).
+See [Section 4.5.1.2 of RFC 4511] for details.
+
+[ldap/v3]: https://pkg.go.dev/github.com/go-ldap/ldap/v3
+[ldap/v3.ScopeSingleLevel]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#ScopeSingleLevel
+[Section 4.5.1.2 of RFC 4511]: https://datatracker.ietf.org/doc/html/rfc4511#section-4.5.1.2
+*/
+const (
+	BaseObjectScope = iota
+	SingleLevelScope
+	WholeSubtreeScope
+)
+
+/*
+DotNotationSearchRequest returns the LDAP Search Request values needed to
+locate the *[Registration] bearing the input "[dotNotation]" value under
+the terms of the receiver instance, alongside an error. The base, scope,
+filter and attribute return values are compatible as input to both the
+[ldap/v3.NewSearchRequest] function and the [Directory.Search] method.
+
+As the DN of such a registration is derived from its number forms in both
+models, the exact DN is used with a [BaseObjectScope]:
+
+  - "n=6,n=3,n=1,<base>" ([ThreeDimensional])
+  - "dotNotation=1.3.6,<base>" ([TwoDimensional])
+
+The registration base is that to which the input value is routed through
+[DITProfile.SetOIDRoute], else the base selected by [DITProfile.RegistrationBase].
+
+[dotNotation]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.2
+[ldap/v3.NewSearchRequest]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#NewSearchRequest
+*/
+func (r *DITProfile) DotNotationSearchRequest(dot string) (base string, scope int, filter string, at []string, err error) {
+	if err = r.checkSearchProfile(); err != nil {
+		return
+	} else if !IsNumericOID(dot) && !strInSlice(dot, []string{`0`, `1`, `2`}) {
+		err = wrapErr(InvalidOIDErr, "'"+dot+"'")
+		return
+	}
+
+	sup := r.RegistrationBase()
+	if idx, _ := r.OIDRoute(dot); idx >= 0 {
+		sup = r.registrationBase(idx)
+	}
+
+	base = r.dotNotationDN(dot, sup)
+	scope, filter, at = BaseObjectScope, DefaultRegistrationSearchItem, AttributeSelector{}.All()
+
+	return
+}
+
+/*
+ASN1NotationSearchRequest returns the LDAP Search Request values needed to
+locate the *[Registration] bearing the input "[aSN1Notation]" value, such
+as "{iso identified-organization(3) dod(6)}", alongside an error.
+
+As every arc of such a value bears a number form, the corresponding
+"[dotNotation]" value is derived, and the return values are those of
+[DITProfile.DotNotationSearchRequest].
+
+[aSN1Notation]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.4
+[dotNotation]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.2
+*/
+func (r *DITProfile) ASN1NotationSearchRequest(asn string) (base string, scope int, filter string, at []string, err error) {
+	var multi []string
+	if multi, err = ASN1NotationToMulti(asn); err != nil {
+		return
+	}
+
+	// A sole root arc yields no dotNotation,
+	// but its number form is the last value.
+	dot := multi[1]
+	if len(dot) == 0 {
+		dot = multi[4]
+	}
+
+	return r.DotNotationSearchRequest(dot)
+}
+
+/*
+IdentifierSearchRequest returns the LDAP Search Request values needed to
+locate those *[Registration] instances bearing the input "[identifier]"
+value, alongside an error.
+
+As an identifier is neither unique nor present within the DN, the selected
+[DITProfile.RegistrationBase] is searched using a [SingleLevelScope] under
+the [TwoDimensional] model -- all registrations being its immediate
+subordinates -- and a [WholeSubtreeScope] under the [ThreeDimensional] model.
+
+[identifier]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.7
+*/
+func (r *DITProfile) IdentifierSearchRequest(id string) (base string, scope int, filter string, at []string, err error) {
+	if !IsIdentifier(id) {
+		err = wrapErr(InvalidIdentifierErr, "'"+id+"'")
+		return
+	}
+
+	return r.registrationSearchRequest(`identifier`, id)
+}
+
+/*
+IRISearchRequest returns the LDAP Search Request values needed to locate
+the *[Registration] bearing the input "[iRI]" value, such as "/ISO/Registration-Authority",
+alongside an error.
+
+See [DITProfile.IdentifierSearchRequest] for details regarding the base
+and scope.
+
+[iRI]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.3
+*/
+func (r *DITProfile) IRISearchRequest(iri string) (base string, scope int, filter string, at []string, err error) {
+	if len(iri) < 2 || iri[0] != '/' {
+		err = wrapErr(InvalidIdentifierErr, "IRI '"+iri+"' is not absolute")
+		return
+	}
+
+	return r.registrationSearchRequest(`iRI`, iri)
+}
+
+/*
+UnicodeValueSearchRequest returns the LDAP Search Request values needed to
+locate those *[Registration] instances bearing the input "[unicodeValue]",
+alongside an error.
+
+See [DITProfile.IdentifierSearchRequest] for details regarding the base
+and scope.
+
+[unicodeValue]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.5
+*/
+func (r *DITProfile) UnicodeValueSearchRequest(uval string) (base string, scope int, filter string, at []string, err error) {
+	if len(uval) == 0 || idxr(uval, '/') >= 0 {
+		err = wrapErr(InvalidIdentifierErr, "unicodeValue '"+uval+"'")
+		return
+	}
+
+	return r.registrationSearchRequest(`unicodeValue`, uval)
+}
+
+/*
+RegistrantIDSearchRequest returns the LDAP Search Request values needed to
+locate the *[Registrant] bearing the input "[registrantID]" value, alongside
+an error.
+
+The exact DN, "registrantID=<id>,<base>", is used with a [BaseObjectScope],
+wherein the base is that selected by [DITProfile.RegistrantBase]. As only
+dedicated registrant entries bear such a value, [RegistrantPolicyErr] is
+returned unless the receiver is [DITProfile.Dedicated].
+
+[registrantID]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.34
+*/
+func (r *DITProfile) RegistrantIDSearchRequest(id string) (base string, scope int, filter string, at []string, err error) {
+	if err = r.checkSearchProfile(); err != nil {
+		return
+	} else if !r.Dedicated() {
+		err = RegistrantPolicyErr
+		return
+	} else if len(id) == 0 {
+		err = wrapErr(InvalidIdentifierErr, "zero length registrantID")
+		return
+	}
+
+	base = `registrantID=` + escapeDNValue(id) + `,` + r.RegistrantBase()
+	scope, filter, at = BaseObjectScope, DefaultRegistrantSearchItem, AttributeSelector{}.All()
+
+	return
+}
+
+/*
+registrationSearchRequest returns LDAP Search Request values matching those
+registrations whose typ value equals the input value, which is escaped.
+*/
+func (r *DITProfile) registrationSearchRequest(typ, value string) (base string, scope int, filter string, at []string, err error) {
+	if err = r.checkSearchProfile(); err != nil {
+		return
+	}
+
+	if base, scope = r.RegistrationBase(), WholeSubtreeScope; r.Model() == TwoDimensional {
+		scope = SingleLevelScope
+	}
+
+	filter = `(&` + DefaultRegistrationSearchItem + `(` + typ + `=` + escapeFilterValue(value) + `))`
+	at = AttributeSelector{}.All()
+
+	return
+}
+
+func (r *DITProfile) checkSearchProfile() (err error) {
+	if !r.Valid() {
+		err = DUAConfigValidityErr
+	} else if r.Model() == `` {
+		err = InvalidDimensionErr
+	}
+
+	return
+}
+
+/*
+escapeDNValue returns the input value with those characters which are
+significant within an [RFC 4514] attribute value escaped.
+
+[RFC 4514]: https://www.rfc-editor.org/rfc/rfc4514.html#section-2.4
+*/
+func escapeDNValue(val string) string {
+	bld := newBuilder()
+	for i := 0; i < len(val); i++ {
+		switch c := val[i]; {
+		case c == 0:
+			bld.WriteString(`\00`)
+		case c == ',' || c == '+' || c == '"' || c == '\\' ||
+			c == '<' || c == '>' || c == ';' || c == '=',
+			c == '#' && i == 0,
+			c == ' ' && (i == 0 || i == len(val)-1):
+			bld.WriteByte('\\')
+			bld.WriteByte(c)
+		default:
+			bld.WriteByte(c)
+		}
+	}
+
+	return bld.String()
+}
+
+/*
+Modification operations, each of which corresponds to the respective
+operation constant of [ldap/v3] (e.g.: AddModification is equivalent to
+[ldap/v3.AddAttribute]).
+
+[ldap/v3]: https://pkg.go.dev/github.com/go-ldap/ldap/v3
+[ldap/v3.AddAttribute]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#AddAttribute
+*/
+const (
+	AddModification uint = iota
+	DeleteModification
+	ReplaceModification
+)
+
+/*
+Modification describes a single change to an attribute of an LDAP entry.
+
+A sequence of instances of this type, grouped by DN, may be transcribed
+into [ldap/v3.ModifyRequest] instances by way of the Add, Delete and Replace
+methods of that type, respectively.
+
+[ldap/v3.ModifyRequest]: https://pkg.go.dev/github.com/go-ldap/ldap/v3#ModifyRequest
+*/
+type Modification struct {
+	DN        string   // DN of the entry being modified
+	Operation uint     // AddModification, DeleteModification or ReplaceModification
+	Type      string   // attribute type
+	Values    []string // values added, deleted or replaced
+}
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r Modification) String() (s string) {
+	op := `add`
+	switch r.Operation {
+	case DeleteModification:
+		op = `delete`
+	case ReplaceModification:
+		op = `replace`
+	}
+
+	s = sprintf("%s: %s %s %v", r.DN, op, r.Type, r.Values)
+
+	return
+}
+
+/*
+diffEntries returns slices of [Modification] describing the changes needed
+to transform the before entry into the after entry, both being instances
+of map[string][]string as produced by the various Unmarshal methods.
+
+Single values which differ are replaced, while multi-valued types have
+individual values deleted and added. Case is not significant when values
+are compared. Modifications are ordered by attribute type.
+*/
+func diffEntries(dn string, before, after map[string][]string) (mods []Modification) {
+	var types []string
+	for _, m := range []map[string][]string{before, after} {
+		for at := range m {
+			if !strInSlice(at, types) && !eq(at, `dn`) {
+				types = append(types, at)
+			}
+		}
+	}
+	sortStrs(types)
+
+	for _, at := range types {
+		b, a := before[at], after[at]
+
+		var added, removed []string
+		for _, v := range a {
+			if !strInSlice(v, b) {
+				added = append(added, v)
+			}
+		}
+		for _, v := range b {
+			if !strInSlice(v, a) {
+				removed = append(removed, v)
+			}
+		}
+
+		switch {
+		case len(added) > 0 && len(a) == 1 && len(b) == 1:
+			mods = append(mods, Modification{DN: dn, Operation: ReplaceModification, Type: at, Values: a})
+		default:
+			if len(removed) > 0 {
+				mods = append(mods, Modification{DN: dn, Operation: DeleteModification, Type: at, Values: removed})
+			}
+			if len(added) > 0 {
+				mods = append(mods, Modification{DN: dn, Operation: AddModification, Type: at, Values: added})
+			}
+		}
+	}
+
+	return
+}
+
 /*
 AttributeSelector is a convenience type which extends methods meant to
 streamline the attribute selection process during LDAP Search Request
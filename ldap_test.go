@@ -1,6 +1,7 @@
 package radir
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -113,3 +114,197 @@ func TestLDAP_codecov(t *testing.T) {
 	tokenizeDN("uid=jesse+gidNumber=5042,ou=People,o=example")
 	tokenizeDN(`cn=acme\, co,ou=Organizations,dc=example,dc=com`)
 }
+
+func TestMemoryDirectory(t *testing.T) {
+	prof := &DITProfile{R_Settings: newProfileSettings(), R_DN: `cn=Profile,o=rA`}
+	prof.SetModel(ThreeDimensional)
+	prof.SetRegistrationBase(`ou=Registrations,o=rA`)
+	prof.SetRegistrantBase(`ou=Registrants,o=rA`)
+
+	ant := prof.NewRegistrant()
+	ant.SetDN(`registrantID=X,ou=Registrants,o=rA`)
+	ant.SetID(`X`)
+	ant.CurrentAuthority().SetCN(`Authority`)
+
+	iso := prof.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X680().SetIdentifier(`iso`)
+	org := iso.NewChild(`3`, `identified-organization`)
+	iso.NewSubentry(`wide`).SetCTTL(`3600`)
+
+	var dir Directory
+	mem, err := NewMemoryDirectory(prof, Registrations{iso}, Registrants{ant})
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+	dir = mem
+
+	if entries, err := dir.Search(`o=rA`, WholeSubtreeScope, `(objectClass=registration)`, nil); err != nil || len(entries) != 2 {
+		t.Errorf("%s failed: want 2 registrations, got %d (%v)", t.Name(), len(entries), err)
+		return
+	} else if entries, _ = dir.Search(iso.DN(), SingleLevelScope, `(&(objectClass=subentry)(c-rATTL>=60))`,
+		[]string{`c-rATTL`}); len(entries) != 1 || entries[0][`c-rATTL`][0] != `3600` || len(entries[0][`cn`]) != 0 {
+		t.Errorf("%s failed: unexpected subentry search result %v", t.Name(), entries)
+		return
+	}
+
+	dod := map[string][]string{
+		`dn`:          {`n=6,n=3,n=1,ou=Registrations,o=rA`},
+		`objectClass`: {`top`, `registration`, `arc`, `x680Context`},
+		`n`:           {`6`},
+		`dotNotation`: {`1.3.6`},
+		`identifier`:  {`dod`},
+	}
+	if err = dir.Add(dod); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if err = dir.Add(dod); err == nil {
+		t.Errorf("%s failed: expected error for existing entry", t.Name())
+		return
+	} else if reg := org.Children().Get(`6`); reg.IsZero() || reg.Parent() != org {
+		t.Errorf("%s failed: added registration not linked into tree", t.Name())
+		return
+	}
+
+	dn := dod[`dn`][0]
+	for _, bad := range []Modification{
+		{DN: dn, Operation: DeleteModification, Type: `n`},
+		{DN: dn, Operation: AddModification, Type: `identifier`, Values: []string{`DOD`}},
+		{DN: dn, Operation: DeleteModification, Type: `description`},
+	} {
+		if err = dir.Modify(bad); err == nil {
+			t.Errorf("%s failed: expected error for %s", t.Name(), bad)
+			return
+		}
+	}
+
+	if err = dir.Modify(Modification{DN: dn, Operation: 9, Type: `description`,
+		Values: []string{`x`}}); !errors.Is(err, InvalidModificationErr) {
+		t.Errorf("%s failed: expected InvalidModificationErr, got %v", t.Name(), err)
+		return
+	}
+
+	if err = dir.Modify(Modification{DN: dn, Operation: AddModification,
+		Type: `description`, Values: []string{`US (DoD)`}}); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if entries, _ := dir.Search(iso.DN(), WholeSubtreeScope,
+		`(description=us \28dod\29)`, []string{`n`}); len(entries) != 1 || len(entries[0]) != 2 {
+		t.Errorf("%s failed: unexpected escaped search result %v", t.Name(), entries)
+		return
+	}
+
+	if err = dir.Delete(org.DN()); !errors.Is(err, NotAllowedOnNonLeafErr) {
+		t.Errorf("%s failed: expected error for non-leaf deletion", t.Name())
+		return
+	} else if err = dir.ModifyDN(org.DN(), `n=30`, false, ``); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if got := org.Children().Get(`6`).DN(); got != `n=6,n=30,n=1,ou=Registrations,o=rA` {
+		t.Errorf("%s failed: subordinate not renamed, got '%s'", t.Name(), got)
+		return
+	} else if got := org.X680().N(); got != `3` || len(org.Children().Get(`6`).Description()) != 1 {
+		t.Errorf("%s failed: want retained number form '3', got '%s'", t.Name(), got)
+		return
+	}
+
+	if err = dir.ModifyDN(`n=6,n=30,n=1,ou=Registrations,o=rA`, `n=7`, true, iso.DN()); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if moved := iso.Children().Get(`7`); moved.IsZero() || moved.X680().N() != `7` || org.Children().Len() != 0 {
+		t.Errorf("%s failed: registration not relocated", t.Name())
+		return
+	} else if err = dir.Delete(moved.DN()); err != nil || iso.Children().Len() != 1 {
+		t.Errorf("%s failed: deletion not reflected in tree (%v)", t.Name(), err)
+		return
+	}
+
+	if _, err = dir.Search(`o=rA`, WholeSubtreeScope, `(n=`, nil); err == nil {
+		t.Errorf("%s failed: expected error for malformed filter", t.Name())
+		return
+	} else if _, err = dir.Search(`ou=Elsewhere,o=rA`, WholeSubtreeScope, ``, nil); err == nil {
+		t.Errorf("%s failed: expected error for missing base", t.Name())
+		return
+	} else if len(mem.Registrants()) != 1 || len(mem.Registrations()) != 1 {
+		t.Errorf("%s failed: unexpected backing instances", t.Name())
+		return
+	}
+}
+
+func TestDITProfile_SearchRequests(t *testing.T) {
+	prof := NewFactoryDefaultDUAConfig().Profile()
+
+	ant := prof.NewRegistrant()
+	ant.SetDN(`registrantID=X\+1,ou=Registrants,o=rA`)
+	ant.SetID(`X+1`)
+
+	iso := prof.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X680().SetIdentifier(`iso`)
+	iso.X680().SetIRI(`/ISO`)
+	iso.X660().SetUnicodeValue(`ISO`)
+	org := iso.NewChild(`3`, `identified-organization`)
+	org.X680().SetIRI(`/ISO/Identified-Organization`)
+	org.X660().SetUnicodeValue(`Identified-Organization`)
+
+	dir, err := NewMemoryDirectory(prof, Registrations{iso}, Registrants{ant})
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	for idx, req := range []struct {
+		build func(string) (string, int, string, []string, error)
+		value string
+		base  string
+		scope int
+	}{
+		{prof.DotNotationSearchRequest, `1.3`, `n=3,n=1,ou=Registrations,o=rA`, BaseObjectScope},
+		{prof.ASN1NotationSearchRequest, `{iso identified-organization(3)}`, `n=3,n=1,ou=Registrations,o=rA`, BaseObjectScope},
+		{prof.ASN1NotationSearchRequest, `{iso(1)}`, `n=1,ou=Registrations,o=rA`, BaseObjectScope},
+		{prof.IdentifierSearchRequest, `identified-organization`, `ou=Registrations,o=rA`, WholeSubtreeScope},
+		{prof.IRISearchRequest, `/ISO/Identified-Organization`, `ou=Registrations,o=rA`, WholeSubtreeScope},
+		{prof.UnicodeValueSearchRequest, `ISO`, `ou=Registrations,o=rA`, WholeSubtreeScope},
+		{prof.RegistrantIDSearchRequest, `X+1`, `registrantID=X\+1,ou=Registrants,o=rA`, BaseObjectScope},
+	} {
+		base, scope, filter, at, err := req.build(req.value)
+		if err != nil {
+			t.Errorf("%s[%d] failed: %v", t.Name(), idx, err)
+			return
+		} else if base != req.base || scope != req.scope {
+			t.Errorf("%s[%d] failed:\nwant: %s (%d)\ngot:  %s (%d)", t.Name(), idx, req.base, req.scope, base, scope)
+			return
+		} else if entries, err := dir.Search(base, scope, filter, at); err != nil || len(entries) != 1 {
+			t.Errorf("%s[%d] failed: want 1 entry, got %d (%v)", t.Name(), idx, len(entries), err)
+			return
+		}
+	}
+
+	if _, _, filter, _, _ := prof.IRISearchRequest(`/ISO/*`); filter != `(&(objectClass=registration)(iRI=/ISO/\2a))` {
+		t.Errorf("%s failed: unescaped filter %s", t.Name(), filter)
+		return
+	} else if _, _, _, _, err = prof.DotNotationSearchRequest(`1.x`); !errors.Is(err, InvalidOIDErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), InvalidOIDErr, err)
+		return
+	} else if _, _, _, _, err = prof.IdentifierSearchRequest(`Not An Identifier`); !errors.Is(err, InvalidIdentifierErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), InvalidIdentifierErr, err)
+		return
+	}
+
+	flat := &DITProfile{R_Settings: newProfileSettings()}
+	flat.SetModel(TwoDimensional)
+	flat.SetRegistrationBase(`ou=Registrations,o=rA`)
+	if base, scope, _, _, _ := flat.DotNotationSearchRequest(`1.3.6`); base != `dotNotation=1.3.6,ou=Registrations,o=rA` || scope != BaseObjectScope {
+		t.Errorf("%s failed: unexpected 2D base %s (%d)", t.Name(), base, scope)
+		return
+	} else if _, scope, _, _, _ = flat.IdentifierSearchRequest(`dod`); scope != SingleLevelScope {
+		t.Errorf("%s failed: want single level scope under 2D, got %d", t.Name(), scope)
+		return
+	} else if _, _, _, _, err = flat.RegistrantIDSearchRequest(`X`); err != RegistrantPolicyErr {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), RegistrantPolicyErr, err)
+		return
+	}
+}
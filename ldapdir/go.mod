@@ -0,0 +1,17 @@
+module github.com/oid-directory/go-radir/ldapdir
+
+go 1.20
+
+require (
+	github.com/go-ldap/ldap/v3 v3.4.6
+	github.com/oid-directory/go-radir v0.0.0
+)
+
+require (
+	github.com/Azure/go-ntlmssp v0.0.0-20221128193559-754e69321358 // indirect
+	github.com/go-asn1-ber/asn1-ber v1.5.5 // indirect
+	github.com/google/uuid v1.3.1 // indirect
+	golang.org/x/crypto v0.14.0 // indirect
+)
+
+replace github.com/oid-directory/go-radir => ../
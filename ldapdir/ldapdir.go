@@ -0,0 +1,349 @@
+/*
+Package ldapdir implements the [radir.Directory] interface over a [go-ldap/v3]
+client connection, such as *[ldap.Conn].
+
+This package resides within a module of its own, thus allowing the radir
+package itself to remain free of any go-ldap/v3 dependency.
+
+Searches are paged per [RFC 2696], and LDAP result codes are translated into
+the corresponding errors of the radir package, which may be matched using
+[errors.Is]. The underlying *[ldap.Error] remains available through the use
+of [errors.As].
+
+[go-ldap/v3]: https://pkg.go.dev/github.com/go-ldap/ldap/v3
+[RFC 2696]: https://www.rfc-editor.org/rfc/rfc2696.html
+*/
+package ldapdir
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/oid-directory/go-radir"
+)
+
+/*
+DefaultPageSize is the number of entries requested per page of a search,
+unless otherwise specified through [Directory.SetPageSize].
+*/
+const DefaultPageSize uint32 = 500
+
+/*
+Directory implements [radir.Directory] over an [ldap.Client] instance.
+
+Instances of this type are created using the [New] function.
+*/
+type Directory struct {
+	conn     ldap.Client
+	profile  *radir.DITProfile
+	pageSize uint32
+}
+
+var _ radir.Directory = (*Directory)(nil)
+
+/*
+New returns a new instance of *[Directory] which operates over the input
+[ldap.Client] instance (e.g.: *[ldap.Conn]), alongside an error. The caller
+remains responsible for establishing, binding and closing the connection.
+
+The input *[radir.DITProfile] is used to initialize those instances returned
+by [Directory.SearchRegistrations] and [Directory.SearchRegistrants].
+*/
+func New(conn ldap.Client, profile *radir.DITProfile) (dir *Directory, err error) {
+	if conn == nil {
+		err = radir.NilInstanceErr
+		return
+	} else if !profile.Valid() {
+		err = radir.DUAConfigValidityErr
+		return
+	}
+
+	dir = &Directory{
+		conn:     conn,
+		profile:  profile,
+		pageSize: DefaultPageSize,
+	}
+
+	return
+}
+
+/*
+SetPageSize assigns the number of entries requested per page of a search,
+returning the receiver. A size of zero (0) disables paging.
+*/
+func (r *Directory) SetPageSize(size uint32) *Directory {
+	r.pageSize = size
+	return r
+}
+
+/*
+Profile returns the *[radir.DITProfile] instance in use by the receiver.
+*/
+func (r *Directory) Profile() *radir.DITProfile {
+	return r.profile
+}
+
+/*
+Search returns all entries which reside within the scope of the base DN and
+which match the input filter, alongside an error. The scope is one of the
+radir search scope constants (e.g.: [radir.SingleLevelScope]). If attribute
+types are specified, only those are requested.
+
+Each entry bears its DN by way of the "dn" key. Aliases are never
+dereferenced.
+*/
+func (r *Directory) Search(base string, scope int, filter string, attrs []string) (entries []map[string][]string, err error) {
+	var results []*ldap.Entry
+	if results, err = r.search(base, scope, filter, attrs); err == nil {
+		for _, result := range results {
+			entry := map[string][]string{`dn`: {result.DN}}
+			for _, attr := range result.Attributes {
+				entry[attr.Name] = attr.Values
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return
+}
+
+/*
+SearchRegistrations returns the entries found in the manner of [Directory.Search],
+marshaled into *[radir.Registration] instances by way of [radir.Registrations.Marshal],
+alongside an error. All user and operational types are requested.
+*/
+func (r *Directory) SearchRegistrations(base string, scope int, filter string) (regs radir.Registrations, err error) {
+	var meths []func(any) error
+	if meths, err = r.searchMeths(base, scope, filter); err == nil {
+		err = regs.Marshal(r.profile, meths...)
+	}
+
+	return
+}
+
+/*
+SearchRegistrants returns the entries found in the manner of [Directory.Search],
+marshaled into *[radir.Registrant] instances by way of [radir.Registrants.Marshal],
+alongside an error. All user and operational types are requested.
+*/
+func (r *Directory) SearchRegistrants(base string, scope int, filter string) (ants radir.Registrants, err error) {
+	var meths []func(any) error
+	if meths, err = r.searchMeths(base, scope, filter); err == nil {
+		err = ants.Marshal(r.profile, meths...)
+	}
+
+	return
+}
+
+/*
+Add creates the input entry, alongside an error. Those operational types
+which cannot be supplied by a client (e.g.: "structuralObjectClass") are
+not submitted.
+*/
+func (r *Directory) Add(entry map[string][]string) (err error) {
+	var dn string
+	if dns := entry[`dn`]; len(dns) > 0 {
+		dn = dns[0]
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	for at, vals := range entry {
+		if at != `dn` && len(vals) > 0 && !readOnlyType(at) {
+			req.Attribute(at, vals)
+		}
+	}
+
+	err = translate(r.conn.Add(req))
+
+	return
+}
+
+/*
+AddRegistration creates the entry of the input *[radir.Registration] by way
+of [radir.Registration.Unmarshal], alongside an error.
+*/
+func (r *Directory) AddRegistration(reg *radir.Registration) (err error) {
+	if reg.IsZero() {
+		err = radir.NilRegistrationErr
+		return
+	}
+
+	return r.Add(reg.Unmarshal())
+}
+
+/*
+AddRegistrant creates the entry of the input *[radir.Registrant] by way of
+[radir.Registrant.Unmarshal], alongside an error.
+*/
+func (r *Directory) AddRegistrant(ant *radir.Registrant) (err error) {
+	if ant.IsZero() {
+		err = radir.NilRegistrantErr
+		return
+	}
+
+	return r.Add(ant.Unmarshal())
+}
+
+/*
+Modify submits the input [radir.Modification] instances, all of which must
+bear the same DN, within a single LDAP Modify Request, alongside an error.
+*/
+func (r *Directory) Modify(mods ...radir.Modification) (err error) {
+	if len(mods) == 0 {
+		err = radir.NilArgumentsErr
+		return
+	}
+
+	req := ldap.NewModifyRequest(mods[0].DN, nil)
+	for _, mod := range mods {
+		if !sameDN(mod.DN, req.DN) {
+			err = fmt.Errorf("%w: modifications of %s and %s cannot be combined",
+				radir.InvalidDNErr, req.DN, mod.DN)
+			return
+		}
+
+		switch mod.Operation {
+		case radir.AddModification:
+			req.Add(mod.Type, mod.Values)
+		case radir.DeleteModification:
+			req.Delete(mod.Type, mod.Values)
+		case radir.ReplaceModification:
+			req.Replace(mod.Type, mod.Values)
+		default:
+			err = fmt.Errorf("%w: %d", radir.InvalidModificationErr, mod.Operation)
+			return
+		}
+	}
+
+	err = translate(r.conn.Modify(req))
+
+	return
+}
+
+/*
+sameDN returns a Boolean value indicative of whether the input DNs name the
+same entry, regardless of case and insignificant whitespace. DNs that cannot
+be parsed are compared case-insensitively.
+*/
+func sameDN(a, b string) bool {
+	da, aerr := ldap.ParseDN(a)
+	db, berr := ldap.ParseDN(b)
+	if aerr != nil || berr != nil {
+		return strings.EqualFold(a, b)
+	}
+
+	return da.EqualFold(db)
+}
+
+/*
+Delete removes the entry bearing the input DN, alongside an error.
+*/
+func (r *Directory) Delete(dn string) error {
+	return translate(r.conn.Del(ldap.NewDelRequest(dn, nil)))
+}
+
+/*
+ModifyDN assigns the input RDN to the entry bearing the input DN, relocating
+it beneath the new superior DN if non-zero, alongside an error.
+*/
+func (r *Directory) ModifyDN(dn, newRDN string, deleteOldRDN bool, newSuperior string) error {
+	return translate(r.conn.ModifyDN(ldap.NewModifyDNRequest(dn, newRDN, deleteOldRDN, newSuperior)))
+}
+
+/*
+search returns the entries returned by one or more paged LDAP Search
+Requests, alongside an error.
+*/
+func (r *Directory) search(base string, scope int, filter string, attrs []string) (entries []*ldap.Entry, err error) {
+	req := ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases,
+		0, 0, false, filter, attrs, nil)
+
+	var paging *ldap.ControlPaging
+	if r.pageSize > 0 {
+		paging = ldap.NewControlPaging(r.pageSize)
+		req.Controls = append(req.Controls, paging)
+	}
+
+	for {
+		var res *ldap.SearchResult
+		if res, err = r.conn.Search(req); err != nil {
+			entries, err = nil, translate(err)
+			return
+		}
+		entries = append(entries, res.Entries...)
+
+		if paging == nil {
+			break
+		}
+
+		ctrl, ok := ldap.FindControl(res.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+		if !ok || len(ctrl.Cookie) == 0 {
+			break
+		}
+		paging.SetCookie(ctrl.Cookie)
+	}
+
+	return
+}
+
+func (r *Directory) searchMeths(base string, scope int, filter string) (meths []func(any) error, err error) {
+	var entries []*ldap.Entry
+	if entries, err = r.search(base, scope, filter, []string{`*`, `+`}); err == nil {
+		for _, entry := range entries {
+			meths = append(meths, entry.Unmarshal)
+		}
+	}
+
+	return
+}
+
+/*
+resultErrors maps LDAP result codes to their radir error counterparts.
+*/
+var resultErrors = map[uint16]error{
+	ldap.LDAPResultNoSuchAttribute:        radir.NoSuchAttributeErr,
+	ldap.LDAPResultAttributeOrValueExists: radir.AttributeOrValueExistsErr,
+	ldap.LDAPResultNoSuchObject:           radir.NoSuchObjectErr,
+	ldap.LDAPResultInvalidDNSyntax:        radir.InvalidDNErr,
+	ldap.LDAPResultObjectClassViolation:   radir.ObjectClassViolationErr,
+	ldap.LDAPResultNotAllowedOnNonLeaf:    radir.NotAllowedOnNonLeafErr,
+	ldap.LDAPResultNotAllowedOnRDN:        radir.NotAllowedOnRDNErr,
+	ldap.LDAPResultEntryAlreadyExists:     radir.EntryExistsErr,
+	ldap.ErrorFilterCompile:               radir.InvalidFilterErr,
+}
+
+/*
+translate returns the input error wrapped alongside the radir error which
+corresponds to its LDAP result code, if any. Otherwise, the input error is
+returned as-is.
+*/
+func translate(err error) error {
+	var lerr *ldap.Error
+	if errors.As(err, &lerr) {
+		if rerr, found := resultErrors[lerr.ResultCode]; found {
+			err = fmt.Errorf("%w: %w", rerr, err)
+		}
+	}
+
+	return err
+}
+
+/*
+readOnlyType returns a Boolean value indicative of whether the input type
+is an operational type which cannot be supplied by a client.
+*/
+func readOnlyType(at string) bool {
+	for _, ro := range []string{
+		`collectiveAttributeSubentries`,
+		`governingStructureRule`,
+		`structuralObjectClass`,
+	} {
+		if strings.EqualFold(at, ro) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,228 @@
+package ldapdir
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/oid-directory/go-radir"
+)
+
+/*
+memoryClient is an [ldap.Client] stand-in backed by a *[radir.MemoryDirectory].
+Only those methods used by *[Directory] are implemented.
+*/
+type memoryClient struct {
+	ldap.Client
+	mem      *radir.MemoryDirectory
+	searches int
+}
+
+var clientErrors = map[error]uint16{
+	radir.NoSuchAttributeErr:        ldap.LDAPResultNoSuchAttribute,
+	radir.AttributeOrValueExistsErr: ldap.LDAPResultAttributeOrValueExists,
+	radir.NoSuchObjectErr:           ldap.LDAPResultNoSuchObject,
+	radir.InvalidDNErr:              ldap.LDAPResultInvalidDNSyntax,
+	radir.ObjectClassViolationErr:   ldap.LDAPResultObjectClassViolation,
+	radir.NotAllowedOnNonLeafErr:    ldap.LDAPResultNotAllowedOnNonLeaf,
+	radir.NotAllowedOnRDNErr:        ldap.LDAPResultNotAllowedOnRDN,
+	radir.EntryExistsErr:            ldap.LDAPResultEntryAlreadyExists,
+}
+
+func (r *memoryClient) result(err error) error {
+	for rerr, code := range clientErrors {
+		if errors.Is(err, rerr) {
+			return ldap.NewError(code, err)
+		}
+	}
+
+	return err
+}
+
+func (r *memoryClient) Search(req *ldap.SearchRequest) (res *ldap.SearchResult, err error) {
+	r.searches++
+	if _, err = ldap.CompileFilter(req.Filter); err != nil {
+		return
+	}
+
+	var entries []map[string][]string
+	if entries, err = r.mem.Search(req.BaseDN, req.Scope, req.Filter, req.Attributes); err != nil {
+		err = r.result(err)
+		return
+	}
+
+	res = new(ldap.SearchResult)
+	start, end := 0, len(entries)
+	paging, _ := ldap.FindControl(req.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+	if paging != nil {
+		if len(paging.Cookie) > 0 {
+			start, _ = strconv.Atoi(string(paging.Cookie))
+		}
+
+		next := ldap.NewControlPaging(paging.PagingSize)
+		if end = start + int(paging.PagingSize); end < len(entries) {
+			next.SetCookie([]byte(strconv.Itoa(end)))
+		} else {
+			end = len(entries)
+		}
+		res.Controls = append(res.Controls, next)
+	}
+
+	for _, entry := range entries[start:end] {
+		attrs := make(map[string][]string)
+		for at, vals := range entry {
+			if at != `dn` {
+				attrs[at] = vals
+			}
+		}
+		res.Entries = append(res.Entries, ldap.NewEntry(entry[`dn`][0], attrs))
+	}
+
+	return
+}
+
+func (r *memoryClient) Add(req *ldap.AddRequest) error {
+	entry := map[string][]string{`dn`: {req.DN}}
+	for _, attr := range req.Attributes {
+		entry[attr.Type] = attr.Vals
+	}
+
+	return r.result(r.mem.Add(entry))
+}
+
+func (r *memoryClient) Modify(req *ldap.ModifyRequest) error {
+	var mods []radir.Modification
+	for _, change := range req.Changes {
+		mods = append(mods, radir.Modification{
+			DN:        req.DN,
+			Operation: uint(change.Operation),
+			Type:      change.Modification.Type,
+			Values:    change.Modification.Vals,
+		})
+	}
+
+	return r.result(r.mem.Modify(mods...))
+}
+
+func (r *memoryClient) Del(req *ldap.DelRequest) error {
+	return r.result(r.mem.Delete(req.DN))
+}
+
+func (r *memoryClient) ModifyDN(req *ldap.ModifyDNRequest) error {
+	return r.result(r.mem.ModifyDN(req.DN, req.NewRDN, req.DeleteOldRDN, req.NewSuperior))
+}
+
+func TestDirectory(t *testing.T) {
+	prof := radir.NewFactoryDefaultDUAConfig().Profile()
+
+	ant := prof.NewRegistrant()
+	ant.SetDN(`registrantID=X,ou=Registrants,o=rA`)
+	ant.SetID(`X`)
+	ant.CurrentAuthority().SetCN(`Authority`)
+
+	iso := prof.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X680().SetIdentifier(`iso`)
+	iso.NewChild(`3`, `identified-organization`)
+
+	mem, err := radir.NewMemoryDirectory(prof, radir.Registrations{iso}, radir.Registrants{ant})
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	if _, err = New(nil, prof); err == nil {
+		t.Errorf("%s failed: expected error for nil connection", t.Name())
+		return
+	}
+
+	client := &memoryClient{mem: mem}
+	dir, err := New(client, prof)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+	dir.SetPageSize(1)
+
+	var regs radir.Registrations
+	if regs, err = dir.SearchRegistrations(`ou=Registrations,o=rA`, radir.WholeSubtreeScope,
+		`(objectClass=registration)`); err != nil || len(regs) != 2 {
+		t.Errorf("%s failed: want 2 registrations, got %d (%v)", t.Name(), len(regs), err)
+		return
+	} else if client.searches != 2 {
+		t.Errorf("%s failed: want 2 paged searches, got %d", t.Name(), client.searches)
+		return
+	}
+
+	dod := prof.NewRegistration()
+	dod.SetDN(`n=6,n=3,n=1,ou=Registrations,o=rA`)
+	dod.X680().SetN(`6`)
+	dod.X680().SetDotNotation(`1.3.6`)
+	dod.X680().SetIdentifier(`dod`)
+	if err = dir.AddRegistration(dod); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if err = dir.AddRegistration(dod); !errors.Is(err, radir.EntryExistsErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), radir.EntryExistsErr, err)
+		return
+	}
+
+	var lerr *ldap.Error
+	if !errors.As(err, &lerr) || lerr.ResultCode != ldap.LDAPResultEntryAlreadyExists {
+		t.Errorf("%s failed: underlying *ldap.Error not retained: %v", t.Name(), err)
+		return
+	}
+
+	if err = dir.Modify(radir.Modification{DN: dod.DN(), Operation: radir.AddModification,
+		Type: `description`, Values: []string{`US (DoD)`}}); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if err = dir.Modify(
+		radir.Modification{DN: dod.DN(), Operation: radir.DeleteModification, Type: `description`},
+		radir.Modification{DN: iso.DN(), Operation: radir.DeleteModification, Type: `description`},
+	); !errors.Is(err, radir.InvalidDNErr) {
+		t.Errorf("%s failed: expected error for mixed DNs, got %v", t.Name(), err)
+		return
+	} else if err = dir.Modify(
+		radir.Modification{DN: dod.DN(), Operation: radir.AddModification, Type: `description`, Values: []string{`DoD`}},
+		radir.Modification{DN: strings.ToUpper(dod.DN()), Operation: radir.DeleteModification, Type: `description`, Values: []string{`DoD`}},
+	); err != nil {
+		t.Errorf("%s failed: equivalent DNs not combined: %v", t.Name(), err)
+		return
+	} else if err = dir.Modify(radir.Modification{DN: dod.DN(), Operation: 9,
+		Type: `description`}); !errors.Is(err, radir.InvalidModificationErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), radir.InvalidModificationErr, err)
+		return
+	}
+
+	var entries []map[string][]string
+	if entries, err = dir.Search(iso.DN(), radir.WholeSubtreeScope,
+		`(description=us \28dod\29)`, []string{`n`}); err != nil || len(entries) != 1 {
+		t.Errorf("%s failed: unexpected search result %v (%v)", t.Name(), entries, err)
+		return
+	} else if _, err = dir.Search(iso.DN(), radir.BaseObjectScope, `(n=`, nil); !errors.Is(err, radir.InvalidFilterErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), radir.InvalidFilterErr, err)
+		return
+	}
+
+	if err = dir.Delete(iso.DN()); !errors.Is(err, radir.NotAllowedOnNonLeafErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), radir.NotAllowedOnNonLeafErr, err)
+		return
+	} else if err = dir.Delete(dod.DN()); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if err = dir.Delete(dod.DN()); !errors.Is(err, radir.NoSuchObjectErr) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), radir.NoSuchObjectErr, err)
+		return
+	}
+
+	var ants radir.Registrants
+	if ants, err = dir.SearchRegistrants(`ou=Registrants,o=rA`, radir.SingleLevelScope,
+		`(registrantID=X)`); err != nil || len(ants) != 1 || ants[0].ID() != `X` {
+		t.Errorf("%s failed: unexpected registrants %v (%v)", t.Name(), ants, err)
+		return
+	}
+}
@@ -0,0 +1,406 @@
+package radir
+
+/*
+pol.go contains methods for conversion between registrant policies.
+*/
+
+import "reflect"
+
+/*
+PolicyConversion contains the product of a registrant policy conversion
+performed by [Registrations.Dedicate] or [Registrations.Combine].
+*/
+type PolicyConversion struct {
+	Registrations Registrations // all converted registrations, flattened
+	Registrants   Registrants   // extracted registrants; zero following a Combine
+}
+
+/*
+LDIF returns the string LDIF form of all registrants, followed by all
+registrations, present within the receiver instance.
+*/
+func (r PolicyConversion) LDIF() (l string) {
+	l = r.Registrants.LDIF()
+	for i := 0; i < len(r.Registrations); i++ {
+		l += r.Registrations[i].LDIF(0)
+	}
+
+	return
+}
+
+/*
+Dedicate converts all *[Registration] instances within the receiver, as
+well as their descendants, from the "Combined Registrants Policy" to the
+"Dedicated Registrants Policy" defined in [Section 3.2.1.1.1 of the RADIT
+I-D].
+
+Each authority embedded within a registration (see [X660.CombinedFirstAuthority],
+[X660.CombinedCurrentAuthority] and [X660.CombinedSponsor]) is extracted into
+a *[Registrant] instance, whose DN is then written to the "[firstAuthority]",
+"[currentAuthority]" or "[sponsor]" values of the registration, as appropriate.
+
+Authorities are deduplicated. Authorities bearing the same identifying values,
+i.e.: all values except for start and end timestamps, are represented by the
+same *[Registrant] unless their respective roles (first, current or sponsor)
+would conflict. Case is not significant in the matching process.
+
+The input *[DITProfile] must be valid and must define a dedicated registrant
+base. It is assigned to all converted registrations and extracted registrants.
+
+The DN of each new *[Registrant] is generated by the optional [GetOrSetFunc]
+instance. [RegistrantDNGenerator] is used by default. If the RDN of a generated
+DN is of the "[registrantID]" type, its value is also assigned as the ID of
+the *[Registrant].
+
+Note that the receiver's instances are modified in place. An error returned
+during processing may leave the receiver partially converted.
+
+[Section 3.2.1.1.1 of the RADIT I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-radit#section-3.2.1.1.1
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+[registrantID]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.34
+*/
+func (r Registrations) Dedicate(profile *DITProfile, namer ...GetOrSetFunc) (conv PolicyConversion, err error) {
+	if !profile.Valid() {
+		err = DUAConfigValidityErr
+		return
+	} else if !profile.Dedicated() {
+		err = wrapErr(RegistrantPolicyErr, "profile is not dedicated")
+		return
+	}
+
+	var gen GetOrSetFunc = RegistrantDNGenerator
+	if len(namer) > 0 {
+		if gen = namer[0]; gen == nil {
+			err = NilGetOrSetFuncErr
+			return
+		}
+	}
+
+	// identities maps an identity key to all
+	// registrants bearing that identity.
+	identities := make(map[string][]*Registrant)
+
+	for _, reg := range r.flatten() {
+		x := reg.R_X660
+		for role, auth := range []any{x.combinedFirst(), x.combinedCurrent(), x.combinedSponsor()} {
+			if auth == nil {
+				continue
+			}
+
+			id := authorityKey(auth, false)
+			var ath *Registrant
+			for _, cand := range identities[id] {
+				if slot := cand.authority(role); slot == nil ||
+					authorityKey(slot, true) == authorityKey(auth, true) {
+					ath = cand
+					break
+				}
+			}
+
+			if ath == nil {
+				if ath, err = newConvertedRegistrant(profile, gen); err != nil {
+					return
+				}
+				identities[id] = append(identities[id], ath)
+				conv.Registrants = append(conv.Registrants, ath)
+			}
+
+			ath.setAuthority(auth)
+			x.addAuthorityDN(role, ath.DN())
+		}
+
+		if !x.IsZero() {
+			x.R_CFAuthy, x.R_CCAuthy, x.R_CSAuthy = nil, nil, nil
+		}
+		reg.convertProfile(profile)
+		conv.Registrations = append(conv.Registrations, reg)
+	}
+
+	for _, ath := range conv.Registrants {
+		ath.refreshObjectClasses()
+	}
+
+	return
+}
+
+/*
+Combine converts all *[Registration] instances within the receiver, as
+well as their descendants, from the "Dedicated Registrants Policy" to the
+"Combined Registrants Policy" defined in [Section 3.2.1.1.2 of the RADIT
+I-D]. This is the reverse of [Registrations.Dedicate].
+
+Each "[firstAuthority]", "[currentAuthority]" or "[sponsor]" DN value of
+a registration is resolved against the input *[Registrants], and the relevant
+authority content is embedded within the registration. The DN values are
+then removed.
+
+As the "Combined Registrants Policy" allows only one authority of each role
+per registration, an error is returned if a registration references more than
+one authority of the same role. An error is also returned if a reference
+cannot be resolved.
+
+The input *[DITProfile] must be valid and must NOT define a dedicated registrant
+base. It is assigned to all converted registrations.
+
+Note that the receiver's instances are modified in place. An error returned
+during processing may leave the receiver partially converted.
+
+[Section 3.2.1.1.2 of the RADIT I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-radit#section-3.2.1.1.2
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+*/
+func (r Registrations) Combine(profile *DITProfile, registrants Registrants) (conv PolicyConversion, err error) {
+	if !profile.Valid() {
+		err = DUAConfigValidityErr
+		return
+	} else if !profile.Combined() {
+		err = wrapErr(RegistrantPolicyErr, "profile is not combined")
+		return
+	}
+
+	for _, reg := range r.flatten() {
+		if x := reg.R_X660; !x.IsZero() {
+			for role, dns := range [][]string{x.R_FAuthyDN, x.R_CAuthyDN, x.R_SAuthyDN} {
+				if len(dns) > 1 {
+					err = wrapErr(RegistrantPolicyErr,
+						"multiple authorities of the same role in "+reg.DN())
+					return
+				} else if len(dns) == 0 {
+					continue
+				}
+
+				var auth any
+				if ath := registrants.getDN(dns[0]); ath.IsZero() {
+					err = wrapErr(RegistrantPolicyErr,
+						"unresolved authority "+dns[0]+" in "+reg.DN())
+					return
+				} else if auth = ath.authority(role); auth == nil {
+					err = wrapErr(RegistrantPolicyErr,
+						"authority "+dns[0]+" bears no content for its role")
+					return
+				}
+
+				x.setCombinedAuthority(auth)
+			}
+			x.R_FAuthyDN, x.R_CAuthyDN, x.R_SAuthyDN = nil, nil, nil
+		}
+
+		reg.convertProfile(profile)
+		conv.Registrations = append(conv.Registrations, reg)
+	}
+
+	return
+}
+
+/*
+flatten returns all *[Registration] instances within the receiver, as
+well as their descendants, in depth-first order.
+*/
+func (r Registrations) flatten() (regs Registrations) {
+	for i := 0; i < len(r); i++ {
+		regs = append(regs, r[i].progeny(true)...)
+	}
+
+	return
+}
+
+/*
+convertProfile assigns the input *[DITProfile] to the receiver, as well
+as to those embedded instances which retain a reference to the profile,
+and refreshes the receiver's objectClass values.
+*/
+func (r *Registration) convertProfile(profile *DITProfile) {
+	r.R_DITProfile = profile
+	if !r.R_X660.IsZero() {
+		r.R_X660.r_DITProfile = profile
+	}
+	if !r.R_Extra.IsZero() {
+		r.R_Extra.r_DITProfile = profile
+	}
+	r.refreshObjectClasses()
+}
+
+func newConvertedRegistrant(profile *DITProfile, gen GetOrSetFunc) (ath *Registrant, err error) {
+	ath = profile.NewRegistrant()
+
+	var dn any
+	if dn, err = gen(``, ath); err != nil {
+		return
+	} else if err = ath.SetDN(dn); err != nil {
+		return
+	}
+
+	if rdns := rdnSequence(ath.DN()); len(rdns) > 0 {
+		if at, val, _ := rdnAttributeValue(rdns[0]); eq(at, `registrantID`) {
+			err = ath.SetID(val)
+		}
+	}
+
+	return
+}
+
+/*
+getDN returns the *[Registrant] instance bearing the input DN, or nil
+if not found. Case is not significant in the matching process.
+*/
+func (r Registrants) getDN(dn string) (ath *Registrant) {
+	for i := 0; i < len(r); i++ {
+		if eq(r[i].DN(), dn) {
+			ath = r[i]
+			break
+		}
+	}
+
+	return
+}
+
+/*
+authority returns the first (0), current (1) or sponsor (2) authority
+of the receiver instance, or nil if unset or empty.
+*/
+func (r *Registrant) authority(role int) (auth any) {
+	switch role {
+	case 0:
+		if !r.R_FA.IsZero() && !r.R_FA.isEmpty() {
+			auth = r.R_FA
+		}
+	case 1:
+		if !r.R_CA.IsZero() && !r.R_CA.isEmpty() {
+			auth = r.R_CA
+		}
+	case 2:
+		if !r.R_SA.IsZero() && !r.R_SA.isEmpty() {
+			auth = r.R_SA
+		}
+	}
+
+	return
+}
+
+/*
+setAuthority assigns a copy of the input authority to the receiver. The
+role is implied by the type of auth.
+*/
+func (r *Registrant) setAuthority(auth any) {
+	switch tv := auth.(type) {
+	case *FirstAuthority:
+		fa := *tv
+		fa.r_reg = nil
+		r.R_FA = &fa
+	case *CurrentAuthority:
+		ca := *tv
+		ca.r_reg = nil
+		r.R_CA = &ca
+	case *Sponsor:
+		sa := *tv
+		sa.r_reg = nil
+		r.R_SA = &sa
+	}
+}
+
+/*
+setCombinedAuthority assigns a copy of the input authority to the receiver.
+The role is implied by the type of auth.
+*/
+func (r *X660) setCombinedAuthority(auth any) {
+	switch tv := auth.(type) {
+	case *FirstAuthority:
+		fa := *tv
+		fa.r_reg = r.r_reg
+		r.R_CFAuthy = &fa
+	case *CurrentAuthority:
+		ca := *tv
+		ca.r_reg = r.r_reg
+		r.R_CCAuthy = &ca
+	case *Sponsor:
+		sa := *tv
+		sa.r_reg = r.r_reg
+		r.R_CSAuthy = &sa
+	}
+}
+
+/*
+addAuthorityDN appends the input DN to the first (0), current (1) or
+sponsor (2) authority DN values of the receiver, if not already present.
+*/
+func (r *X660) addAuthorityDN(role int, dn string) {
+	dns := []*[]string{&r.R_FAuthyDN, &r.R_CAuthyDN, &r.R_SAuthyDN}[role]
+	if !strInSlice(dn, *dns) {
+		*dns = append(*dns, dn)
+	}
+}
+
+func (r *X660) combinedFirst() (auth any) {
+	if !r.IsZero() && !r.R_CFAuthy.IsZero() && !r.R_CFAuthy.isEmpty() {
+		auth = r.R_CFAuthy
+	}
+	return
+}
+
+func (r *X660) combinedCurrent() (auth any) {
+	if !r.IsZero() && !r.R_CCAuthy.IsZero() && !r.R_CCAuthy.isEmpty() {
+		auth = r.R_CCAuthy
+	}
+	return
+}
+
+func (r *X660) combinedSponsor() (auth any) {
+	if !r.IsZero() && !r.R_CSAuthy.IsZero() && !r.R_CSAuthy.isEmpty() {
+		auth = r.R_CSAuthy
+	}
+	return
+}
+
+/*
+authorityKey returns a string key derived from the values of the input
+authority instance, irrespective of role and of attribute type strategy.
+Start and end timestamps are only considered if timestamps is true.
+*/
+func authorityKey(auth any, timestamps bool) string {
+	var parts []string
+	eachAuthorityValue(auth, func(name string, vals []string) {
+		if timestamps || (name != `R_StartTime` && name != `R_EndTime`) {
+			parts = append(parts, name+`=`+lc(join(vals, "\x00")))
+		}
+	})
+	sortStrs(parts)
+
+	return join(parts, "\x01")
+}
+
+/*
+eachAuthorityValue executes fn for each non-zero field of the input authority
+instance. The name passed to fn is that of the struct field, less any "_alt"
+suffix, thus allowing fields to be correlated irrespective of role and of
+attribute type strategy.
+*/
+func eachAuthorityValue(auth any, fn func(string, []string)) {
+	ot, ov, ok := getReflectInstances(auth)
+	if !ok {
+		return
+	}
+
+	for i := 0; i < ot.NumField(); i++ {
+		f := ot.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		var vals []string
+		switch v := ov.Field(i); v.Kind() {
+		case reflect.String:
+			if len(v.String()) > 0 {
+				vals = []string{v.String()}
+			}
+		case reflect.Slice:
+			vals, _ = v.Interface().([]string)
+		}
+
+		if len(vals) > 0 {
+			fn(trimSfx(f.Name, `_alt`), vals)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package radir
+
+/*
+promo.go contains the staging-to-active promotion workflow.
+*/
+
+/*
+Promotion contains the product of a staging-to-active promotion performed
+by [Registration.Promote].
+*/
+type Promotion struct {
+	Registrations Registrations         // promoted registrations, depth-first
+	Adds          []map[string][]string // entries to add to the active DIT, registrants and parents first
+	Deletes       []string              // DNs to delete from the staging DIT, children first
+}
+
+/*
+LDIF returns the string LDIF form of the receiver instance, comprised of
+change records which add all entries to the active DIT, followed by those
+which delete all entries from the staging DIT.
+*/
+func (r Promotion) LDIF() (l string) {
+	bld := newBuilder()
+	for _, entry := range r.Adds {
+		bld.WriteString(entryLDIF(entry, `add`))
+	}
+
+	for _, dn := range r.Deletes {
+		bld.WriteString(`dn: ` + dn + "\nchangetype: delete\n\n")
+	}
+
+	l = bld.String()
+
+	return
+}
+
+/*
+Promote relocates the receiver, which resides within a staging DIT, to the
+active DIT governed by the input *[DITProfile], returning a [Promotion]
+alongside an error. If subtree is true, all descendants of the receiver are
+promoted alongside it.
+
+This workflow facilitates the "staging" scenario described for *[DITProfile],
+whereby unvetted registrations are reviewed prior to relocation to the active
+DIT following approval. See [Registration.Reject] for the alternative outcome.
+
+The staging profile is that assigned to the receiver. Both profiles must be
+valid, and must share the same directory model and registrant policy. Each
+promoted registration must bear a DN, a number form and the "registration"
+class, and must not violate [Registration.CheckSchema].
+
+The DN of each promoted registration, and of each of its subentries, is
+rewritten such that the staging registration base is replaced with the
+registration base currently selected within the active profile (see
+[DUAConfig.GoverningProfileByOID]). DN values referencing entries beneath
+any base of the staging profile are rewritten likewise, namely:
+
+  - "[firstAuthority]", "[currentAuthority]" and "[sponsor]" values, as well as their collective forms, which are rebased onto the currently selected registrant base
+  - "[seeAlso]" values and [Spatial] values, including their collective forms
+
+Registrants referenced by promoted registrations which reside beneath a
+staging registrant base must be found among the input [Registrants], else
+an error is returned. Such registrants are added to the active DIT ahead of
+the registrations which reference them. They are not deleted from the staging
+DIT, as they may be referenced by other registrations.
+
+The receiver and its descendants and subentries are modified in place, and
+assigned the active profile. Nothing is modified if an error is returned.
+The return value is intended for transmission to the RA DSA(s) involved;
+note that the parent of the receiver must already reside within the active
+DIT, unless the receiver resides directly beneath the base.
+
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+[seeAlso]: https://www.rfc-editor.org/rfc/rfc4519.html#section-2.30
+*/
+func (r *Registration) Promote(active *DITProfile, subtree bool, ants Registrants) (promo Promotion, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	}
+
+	staging := r.R_DITProfile
+	if err = checkPromotionProfiles(staging, active); err != nil {
+		return
+	}
+
+	regs := Registrations{r}
+	if subtree {
+		regs = r.progeny(true)
+	} else if r.Children().Len() > 0 {
+		err = wrapErr(RegistrationValidityErr, r.DN()+" has subordinates; promote the subtree")
+		return
+	}
+
+	for _, reg := range regs {
+		if !reg.valid() {
+			err = wrapErr(RegistrationValidityErr, reg.DN())
+			return
+		} else if v := reg.CheckSchema(); len(v) > 0 {
+			err = wrapErr(RegistrationValidityErr, v[0].String())
+			return
+		} else if _, ok := rebaseDN(reg.DN(), staging.R_RegBase, active.RegistrationBase()); !ok {
+			err = wrapErr(InvalidDNErr, reg.DN()+" is not within the staging DIT")
+			return
+		}
+	}
+
+	p := &promoter{
+		regBase: active.RegistrationBase(),
+		antBase: active.RegistrantBase(),
+		staging: staging,
+		ants:    make(map[string]*Registrant),
+	}
+
+	for _, ant := range ants {
+		if !ant.IsZero() {
+			p.ants[normDN(ant.DN())] = ant
+		}
+	}
+
+	for _, reg := range regs {
+		if err = p.checkAuthorities(reg.DN(), reg.R_X660); err != nil {
+			return
+		} else if reg.r_se != nil {
+			for _, se := range *reg.r_se {
+				if err = p.checkAuthorities(se.DN(), se.R_X660); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	for _, reg := range regs {
+		p.deletes = append(p.deletes, reg.DN())
+		if reg.r_se != nil {
+			for _, se := range *reg.r_se {
+				p.deletes = append(p.deletes, se.DN())
+			}
+		}
+	}
+
+	var adds []map[string][]string
+	for _, reg := range regs {
+		reg.R_DN, _ = rebaseDN(reg.DN(), staging.R_RegBase, p.regBase)
+		p.rebaseRefs(reg.R_X660, reg.R_Spatial)
+		reg.R_Also = p.rebaseValues(`seeAlso`, reg.R_Also)
+		reg.convertProfile(active)
+		promo.Registrations = append(promo.Registrations, reg)
+		adds = append(adds, addableEntry(reg.Unmarshal()))
+
+		if reg.r_se != nil {
+			for _, se := range *reg.r_se {
+				se.R_DN, _ = rebaseDN(se.DN(), staging.R_RegBase, p.regBase)
+				p.rebaseRefs(se.R_X660, se.R_Spatial)
+				se.r_DITProfile = active
+				if !se.R_X660.IsZero() {
+					se.R_X660.r_DITProfile = active
+				}
+				adds = append(adds, addableEntry(se.Unmarshal()))
+			}
+		}
+	}
+
+	// add registrants before the registrations
+	// which reference them.
+	promo.Adds = append(p.adds, adds...)
+
+	// delete children (and subentries) before their parents
+	for i := len(p.deletes) - 1; i >= 0; i-- {
+		promo.Deletes = append(promo.Deletes, p.deletes[i])
+	}
+
+	return
+}
+
+/*
+Reject records the input reason as a "[registrationInformation]" value of
+the receiver, which resides within a staging DIT and has not been approved
+for promotion, returning slices of [Modification] that describe the change
+alongside an error.
+
+The receiver is modified in place, and otherwise remains within the staging
+DIT. See also [Registration.Promote].
+
+[registrationInformation]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.9
+*/
+func (r *Registration) Reject(reason string) (mods []Modification, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	} else if reason = trimS(reason); len(reason) == 0 {
+		err = wrapErr(RegistrationValidityErr, "no rejection reason")
+		return
+	}
+
+	before := r.Unmarshal()
+	if err = r.Supplement().SetInfo(reason); err == nil {
+		r.refreshObjectClasses()
+		mods = diffEntries(r.DN(), before, r.Unmarshal())
+	}
+
+	return
+}
+
+func checkPromotionProfiles(staging, active *DITProfile) (err error) {
+	switch {
+	case !staging.Valid() || !active.Valid():
+		err = DUAConfigValidityErr
+	case staging == active:
+		err = wrapErr(DUAConfigValidityErr, "staging and active profiles are the same")
+	case staging.Model() != active.Model():
+		err = wrapErr(DUAConfigValidityErr, "staging and active models differ")
+	case staging.Combined() != active.Combined() || staging.Dedicated() != active.Dedicated():
+		err = wrapErr(RegistrantPolicyErr, "staging and active policies differ")
+	}
+
+	return
+}
+
+/*
+promoter contains the state of a single promotion.
+*/
+type promoter struct {
+	regBase string // active registration base
+	antBase string // active registrant base
+	staging *DITProfile
+	ants    map[string]*Registrant
+	adds    []map[string][]string // promoted registrants
+	deletes []string
+}
+
+/*
+checkAuthorities returns an error if any authority DN value of the input
+*[X660] instance resides beneath a staging registrant base, but is not
+among the registrants supplied to the receiver.
+*/
+func (r *promoter) checkAuthorities(dn string, x *X660) (err error) {
+	if x.IsZero() || len(r.antBase) == 0 {
+		return
+	}
+
+	for _, tag := range authorityRefTypes {
+		for _, ref := range readFieldByTag(tag, x) {
+			if _, ok := rebaseDN(ref, r.staging.R_AthyBase, r.antBase); ok {
+				if _, found := r.ants[normDN(ref)]; !found {
+					err = wrapErr(RegistrantValidityErr, dn+" references unsupplied registrant "+ref)
+					return
+				}
+			}
+		}
+	}
+
+	return
+}
+
+/*
+rebaseRefs rewrites all authority and spatial DN values of the input
+instances.
+*/
+func (r *promoter) rebaseRefs(x *X660, s *Spatial) {
+	if !x.IsZero() {
+		for _, tag := range authorityRefTypes {
+			writeTagValues(x, tag, r.rebaseValues(tag, readFieldByTag(tag, x)))
+		}
+	}
+
+	if !s.IsZero() {
+		for _, tag := range spatialRefTypes {
+			writeTagValues(s, tag, r.rebaseValues(tag, readFieldByTag(tag, s)))
+		}
+	}
+}
+
+/*
+rebaseValues returns the input DN values, rebased from the staging DIT to
+the active DIT where applicable. Authority values are rebased onto the
+active registrant base, and the corresponding registrants promoted.
+*/
+func (r *promoter) rebaseValues(tag string, vals []string) (out []string) {
+	authority := strInSlice(tag, authorityRefTypes) && len(r.antBase) > 0
+
+	for _, dn := range vals {
+		if ndn, ok := rebaseDN(dn, r.staging.R_AthyBase, r.antBase); authority && ok {
+			r.promoteRegistrant(dn, ndn)
+			dn = ndn
+		} else if ndn, ok = rebaseDN(dn, r.staging.R_RegBase, r.regBase); ok {
+			dn = ndn
+		}
+		out = append(out, dn)
+	}
+
+	return
+}
+
+func (r *promoter) promoteRegistrant(dn, ndn string) {
+	ant, found := r.ants[normDN(dn)]
+	if !found {
+		return
+	}
+
+	delete(r.ants, normDN(dn))
+	entry := addableEntry(ant.Unmarshal())
+	entry[`dn`] = []string{ndn}
+	r.adds = append(r.adds, entry)
+}
+
+/*
+rebaseDN returns the input DN with the longest of the from bases of which
+it is a descendant, or which it is, replaced with the to base. A Boolean
+value indicative of a successful match is also returned.
+*/
+func rebaseDN(dn string, from []string, to string) (out string, ok bool) {
+	rdns := rdnSequence(dn)
+
+	var longest []string
+	for _, base := range from {
+		if sfx := rdnSequence(base); len(sfx) > len(longest) && rdnSuffixEqual(rdns, sfx) {
+			longest = sfx
+		}
+	}
+
+	if ok = len(longest) > 0; ok {
+		rel := append([]string{}, rdns[:len(rdns)-len(longest)]...)
+		out = join(append(rel, rdnSequence(to)...), `,`)
+	}
+
+	return
+}
+
+/*
+addableEntry returns the input entry, sans those operational types which
+cannot be submitted within an LDAP Add request.
+*/
+func addableEntry(entry map[string][]string) map[string][]string {
+	for _, at := range []string{
+		`collectiveAttributeSubentries`,
+		`governingStructureRule`,
+		`structuralObjectClass`,
+	} {
+		delete(entry, at)
+	}
+
+	return entry
+}
+
+/*
+entryLDIF returns the string LDIF change record form of the input entry,
+whose DN and objectClass values are written first, followed by all other
+types in alphabetical order.
+*/
+func entryLDIF(entry map[string][]string, changetype string) string {
+	bld := newBuilder()
+
+	var dn string
+	if dns := entry[`dn`]; len(dns) > 0 {
+		dn = dns[0]
+	}
+	bld.WriteString(`dn: ` + dn + "\nchangetype: " + changetype + "\n")
+
+	var types []string
+	for at := range entry {
+		if at != `dn` && !eq(at, `objectClass`) {
+			types = append(types, at)
+		}
+	}
+	sortStrs(types)
+
+	for _, at := range append([]string{`objectClass`}, types...) {
+		for _, v := range entry[at] {
+			bld.WriteString(at + `: ` + v + "\n")
+		}
+	}
+	bld.WriteRune(10)
+
+	return bld.String()
+}
@@ -0,0 +1,291 @@
+package radir
+
+/*
+refint.go contains referential integrity checks for DN-valued types.
+*/
+
+/*
+IntegrityViolationKind describes the nature of a single referential
+integrity violation reported by [DUAConfig.CheckIntegrity].
+*/
+type IntegrityViolationKind uint8
+
+const (
+	_                      IntegrityViolationKind = iota
+	DanglingReference                             // referenced DN does not resolve to a known entry
+	OutsideRegistrantBase                         // referenced registrant resides outside every registrant base
+	UnreferencedRegistrant                        // registrant is not referenced by any entry
+)
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r IntegrityViolationKind) String() (s string) {
+	switch r {
+	case DanglingReference:
+		s = `dangling reference`
+	case OutsideRegistrantBase:
+		s = `outside registrant base`
+	case UnreferencedRegistrant:
+		s = `unreferenced registrant`
+	}
+
+	return
+}
+
+/*
+IntegrityViolation describes a single DN reference which cannot be
+satisfied, or a registrant which is not referenced at all.
+*/
+type IntegrityViolation struct {
+	DN     string                 // DN of the referencing entry, or of the unreferenced registrant
+	Type   string                 // attribute type bearing the reference, if applicable
+	Target string                 // referenced DN, if applicable
+	Kind   IntegrityViolationKind // nature of the violation
+}
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r IntegrityViolation) String() (s string) {
+	if s = sprintf("%s: %s", r.DN, r.Kind); r.Type != "" {
+		s += sprintf(" %s '%s'", r.Type, r.Target)
+	}
+
+	return
+}
+
+var (
+	authorityRefTypes []string = []string{
+		`firstAuthority`,
+		`currentAuthority`,
+		`sponsor`,
+		`c-firstAuthority;collective`,
+		`c-currentAuthority;collective`,
+		`c-sponsor;collective`,
+	}
+
+	spatialRefTypes []string = []string{
+		`supArc`,
+		`topArc`,
+		`minArc`,
+		`maxArc`,
+		`leftArc`,
+		`rightArc`,
+		`subArc`,
+		`c-supArc;collective`,
+		`c-topArc;collective`,
+		`c-minArc;collective`,
+		`c-maxArc;collective`,
+	}
+)
+
+/*
+integrityCheck contains the state of a single referential integrity
+check.
+*/
+type integrityCheck struct {
+	known       map[string]bool // all entry DNs
+	registrants map[string]bool // registrant DNs only
+	referenced  map[string]bool // registrant DNs referenced by anything
+	bases       [][]string      // registrant bases, as RDN sequences
+	violations  []IntegrityViolation
+}
+
+/*
+CheckIntegrity returns slices of [IntegrityViolation], each describing a DN
+reference which cannot be satisfied by the input [Registrations] trees and
+[Registrants], or by the *[DITProfile] instances of the receiver. An empty
+return value indicates no violations were found.
+
+The following references are checked:
+
+  - "[firstAuthority]", "[currentAuthority]" and "[sponsor]" values, as well as their collective forms, must reference a registrant
+  - "[seeAlso]" values of registrations and registrants must reference any known entry
+  - [Spatial] values, including their collective forms, must reference a registration
+  - "[rADITProfile]" values of the receiver must reference a profile within the receiver
+
+Authority references which resolve, but which reside outside every registrant
+base of the receiver's profiles, are reported as [OutsideRegistrantBase]. This
+check is skipped if no registrant bases are defined. Registrants which are not
+referenced by any entry are reported as [UnreferencedRegistrant].
+
+Subentries of each registration are checked alongside the registration.
+
+Case is not significant when comparing DNs, nor is whitespace surrounding
+RDN separators. This method is intended to supplement, or stand in for, a
+referential integrity mechanism within the RA DSA (e.g.: an OpenLDAP refint
+overlay).
+
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+[seeAlso]: https://www.rfc-editor.org/rfc/rfc4519.html#section-2.30
+[rADITProfile]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.94
+*/
+func (r *DUAConfig) CheckIntegrity(regs Registrations, ants Registrants) (violations []IntegrityViolation) {
+	chk := &integrityCheck{
+		known:       make(map[string]bool),
+		registrants: make(map[string]bool),
+		referenced:  make(map[string]bool),
+	}
+
+	// Unlike governance resolution, integrity checks
+	// honor the DSE and all Profiles alike.
+	var profiles []*DITProfile
+	if r != nil {
+		if !r.R_DSE.IsZero() {
+			profiles = append(profiles, r.R_DSE)
+		}
+		profiles = append(profiles, r.R_Profiles...)
+	}
+
+	for _, prof := range profiles {
+		if prof.IsZero() {
+			continue
+		}
+		if len(prof.R_DN) > 0 {
+			chk.known[normDN(prof.R_DN)] = true
+		}
+		for i := 0; i < prof.NumRegistrantBase(); i++ {
+			chk.bases = append(chk.bases, rdnSequence(prof.registrantBase(i)))
+		}
+	}
+
+	flat := regs.flatten()
+	arcs := make(map[string]bool)
+	for _, reg := range flat {
+		arcs[normDN(reg.DN())] = true
+		chk.known[normDN(reg.DN())] = true
+		if reg.r_se != nil {
+			for _, se := range *reg.r_se {
+				chk.known[normDN(se.R_DN)] = true
+			}
+		}
+	}
+
+	for _, ant := range ants {
+		if !ant.IsZero() {
+			chk.known[normDN(ant.DN())] = true
+			chk.registrants[normDN(ant.DN())] = true
+		}
+	}
+
+	if r != nil {
+		for _, dn := range r.R_ProfileDNs {
+			chk.resolve(``, `rADITProfile`, dn, chk.known)
+		}
+	}
+
+	for _, reg := range flat {
+		chk.checkEntry(reg.DN(), *reg.Children(), reg.R_X660, reg.R_Spatial, arcs)
+		for _, dn := range reg.R_Also {
+			chk.resolve(reg.DN(), `seeAlso`, dn, chk.known)
+		}
+		if reg.r_se != nil {
+			for _, se := range *reg.r_se {
+				chk.checkEntry(se.R_DN, nil, se.R_X660, se.R_Spatial, arcs)
+			}
+		}
+	}
+
+	for _, ant := range ants {
+		if ant.IsZero() {
+			continue
+		}
+		for _, dn := range ant.R_Also {
+			chk.resolve(ant.DN(), `seeAlso`, dn, chk.known)
+		}
+	}
+
+	for _, ant := range ants {
+		if !ant.IsZero() && !chk.referenced[normDN(ant.DN())] {
+			chk.violate(ant.DN(), ``, ``, UnreferencedRegistrant)
+		}
+	}
+
+	violations = chk.violations
+
+	return
+}
+
+/*
+checkEntry checks the authority and spatial references of the input
+entry's *[X660] and *[Spatial] instances. Number form "[subArc]" values
+are resolved against the input children rather than as DNs.
+
+[subArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.25
+*/
+func (r *integrityCheck) checkEntry(dn string, kids Registrations, x *X660, s *Spatial, arcs map[string]bool) {
+	if !x.IsZero() {
+		for _, tag := range authorityRefTypes {
+			for _, ref := range readFieldByTag(tag, x) {
+				if r.resolve(dn, tag, ref, r.registrants) && len(r.bases) > 0 &&
+					!r.inRegistrantBase(ref) {
+					r.violate(dn, tag, ref, OutsideRegistrantBase)
+				}
+			}
+		}
+	}
+
+	if !s.IsZero() {
+		for _, tag := range spatialRefTypes {
+			for _, ref := range readFieldByTag(tag, s) {
+				if tag == `subArc` && isNumber(ref) {
+					if kids.Get(ref).IsZero() {
+						r.violate(dn, tag, ref, DanglingReference)
+					}
+					continue
+				}
+				r.resolve(dn, tag, ref, arcs)
+			}
+		}
+	}
+}
+
+/*
+resolve returns a Boolean value indicative of whether the input reference
+DN is present within the candidates map. A [DanglingReference] violation
+is recorded if not.
+*/
+func (r *integrityCheck) resolve(dn, tag, ref string, candidates map[string]bool) (ok bool) {
+	key := normDN(ref)
+	if r.registrants[key] {
+		r.referenced[key] = true
+	}
+
+	if ok = candidates[key]; !ok {
+		r.violate(dn, tag, ref, DanglingReference)
+	}
+
+	return
+}
+
+func (r *integrityCheck) inRegistrantBase(dn string) (in bool) {
+	rdns := rdnSequence(dn)
+	for i := 0; i < len(r.bases) && !in; i++ {
+		in = len(rdns) > len(r.bases[i]) && rdnSuffixEqual(rdns, r.bases[i])
+	}
+
+	return
+}
+
+func (r *integrityCheck) violate(dn, tag, target string, kind IntegrityViolationKind) {
+	if idx := idxr(tag, ';'); idx != -1 {
+		tag = tag[:idx]
+	}
+	r.violations = append(r.violations, IntegrityViolation{
+		DN:     dn,
+		Type:   tag,
+		Target: target,
+		Kind:   kind,
+	})
+}
+
+/*
+normDN returns the input DN in a form suitable for case-insensitive
+comparison, with whitespace surrounding RDN separators removed.
+*/
+func normDN(dn string) string {
+	return lc(join(rdnSequence(dn), `,`))
+}
@@ -15,6 +15,7 @@ type Registration struct {
 	RC_TTL    string   `ldap:"c-rATTL;collective"`
 	R_SOC     string   `ldap:"structuralObjectClass"`
 	R_CAS     []string `ldap:"collectiveAttributeSubentries"`
+	R_CExcl   []string `ldap:"collectiveExclusions"`
 	R_OC      []string `ldap:"objectClass"`
 	R_Desc    []string `ldap:"description"` // effective "title" of reg
 	R_Also    []string `ldap:"seeAlso"`
@@ -441,11 +442,15 @@ func (r *Registrations) SetYAxes(recursive ...bool) {
 					if tdn != "" {
 						child.Spatial().SetTopArc(tdn)
 					}
-					if recurse && child.r_Children != nil {
-						child.Children().SetYAxes(recurse)
-					}
 				}
 			}
+
+			// Descend only after the receiver's children
+			// have been linked, so that each child's own
+			// topArc is available to its progeny.
+			if recurse && LK > 0 {
+				children.SetYAxes(recurse)
+			}
 		}
 	}
 }
@@ -742,6 +747,44 @@ func (r *Registration) CollectiveAttributeSubentriesGetFunc(getfunc GetOrSetFunc
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `collectiveAttributeSubentries`)
 }
 
+/*
+CollectiveExclusions returns the string "[collectiveExclusions]" values
+assigned to the receiver instance.
+
+[collectiveExclusions]: https://www.rfc-editor.org/rfc/rfc3671.html#section-2.1
+*/
+func (r *Registration) CollectiveExclusions() (excl []string) {
+	if !r.IsZero() {
+		excl = r.R_CExcl
+	}
+
+	return
+}
+
+/*
+SetCollectiveExclusions appends one or more string "[collectiveExclusions]"
+values to the receiver instance. Note that if a slice is passed as X, the
+destination value will be clobbered.
+
+Values may be collective attribute type descriptors, such as "c-rATTL", or
+their numeric OIDs. The "excludeAllCollectiveAttributes" value (2.5.18.0)
+excludes all collective attribute types.
+
+[collectiveExclusions]: https://www.rfc-editor.org/rfc/rfc3671.html#section-2.1
+*/
+func (r *Registration) SetCollectiveExclusions(args ...any) error {
+	return writeFieldByTag(`collectiveExclusions`, r.SetCollectiveExclusions, r, args...)
+}
+
+/*
+CollectiveExclusionsGetFunc processes the underlying field value(s) through
+the provided [GetOrSetFunc] instance, returning an interface value alongside
+an error.
+*/
+func (r *Registration) CollectiveExclusionsGetFunc(getfunc GetOrSetFunc) (any, error) {
+	return getFieldValueByNameTagAndGoSF(r, getfunc, `collectiveExclusions`)
+}
+
 /*
 DN returns the string-based LDAP Distinguished Name value, or a zero
 string if unset.
@@ -862,19 +905,30 @@ func (r *Registration) SeeAlsoGetFunc(getfunc GetOrSetFunc) (any, error) {
 }
 
 /*
-TTL returns the effective time-to-live for the receiver instance, taking
-into account *[DITProfile]-inherited values as well as any subtree-based
-(COLLECTIVE) and entry literal values. The output can be used to instruct
-instances of [Cache] when, and when not, to cache an instance.
-
-See [Section 2.2.3.4 of the RADUA I-D] for details related to TTL precedence.
+TTL returns the literal time-to-live value assigned to the receiver instance,
+or a zero string if unset.
 
-[Section 2.2.3.4 of the RADUA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-radua#section-2.2.3.4
+See [Registration.ResolveTTL] for the effective time-to-live, which takes into
+account *[DITProfile]-inherited values as well as any subtree-based (COLLECTIVE)
+values, and which can be used to instruct instances of [Cache] when, and when
+not, to cache an instance.
 */
 func (r *Registration) TTL() string {
 	return r.R_TTL
 }
 
+/*
+CTTL returns the collective time-to-live value assigned to the receiver
+instance, or a zero string if unset.
+*/
+func (r *Registration) CTTL() (cttl string) {
+	if !r.IsZero() {
+		cttl = r.RC_TTL
+	}
+
+	return
+}
+
 /*
 SetTTL assigns the provided string TTL value to the receiver instance.
 */
@@ -1152,6 +1206,55 @@ func (r *Registration) Dedicated() bool {
 	return false
 }
 
+/*
+effectiveObjectClasses returns the objectClass values of the receiver as
+they would appear following a call of refreshObjectClasses, but without
+modifying (or initializing) any component of the receiver.
+*/
+func (r *Registration) effectiveObjectClasses() (oc []string) {
+	oc = append(oc, r.ObjectClasses()...)
+	for tag, empty := range map[string]bool{
+		`x660Context`:             r.R_X660.IsZero() || r.R_X660.isEmpty(),
+		`x667Context`:             r.R_X667.IsZero() || r.R_X667.isEmpty(),
+		`x680Context`:             r.R_X680.IsZero() || r.R_X680.isEmpty(),
+		`x690Context`:             r.R_X690.IsZero() || r.R_X690.isEmpty(),
+		`registrationSupplement`:  r.R_Extra.IsZero() || r.R_Extra.isEmpty(),
+		`spatialContext`:          r.R_Spatial.IsZero() || r.R_Spatial.isEmpty(),
+		`firstAuthorityContext`:   !r.combinedAuthority(0),
+		`currentAuthorityContext`: !r.combinedAuthority(1),
+		`sponsorContext`:          !r.combinedAuthority(2),
+	} {
+		if empty {
+			oc = removeStrInSlice(tag, oc)
+		} else if !strInSlice(tag, oc) {
+			oc = append(oc, tag)
+		}
+	}
+
+	return
+}
+
+/*
+combinedAuthority returns a Boolean value indicative of whether the Nth
+authority (0: first, 1: current, 2: sponsor) embedded within the receiver
+by way of the "Combined Registrants Policy" bears any content. The
+receiver is not modified.
+*/
+func (r *Registration) combinedAuthority(n int) (has bool) {
+	if x := r.R_X660; !x.IsZero() {
+		switch n {
+		case 0:
+			has = !x.R_CFAuthy.IsZero() && !x.R_CFAuthy.isEmpty()
+		case 1:
+			has = !x.R_CCAuthy.IsZero() && !x.R_CCAuthy.isEmpty()
+		case 2:
+			has = !x.R_CSAuthy.IsZero() && !x.R_CSAuthy.isEmpty()
+		}
+	}
+
+	return
+}
+
 func (r *Registration) refreshObjectClasses() {
 	bools := []bool{
 		r.X660().isEmpty(),
@@ -1160,6 +1263,9 @@ func (r *Registration) refreshObjectClasses() {
 		r.X690().isEmpty(),
 		r.Supplement().isEmpty(),
 		r.Spatial().isEmpty(),
+		!r.combinedAuthority(0),
+		!r.combinedAuthority(1),
+		!r.combinedAuthority(2),
 	}
 
 	for tx, tag := range []string{
@@ -1169,6 +1275,9 @@ func (r *Registration) refreshObjectClasses() {
 		`x690Context`,
 		`registrationSupplement`,
 		`spatialContext`,
+		`firstAuthorityContext`,
+		`currentAuthorityContext`,
+		`sponsorContext`,
 	} {
 		if bools[tx] {
 			r.R_OC = removeStrInSlice(tag, r.R_OC)
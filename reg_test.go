@@ -1,8 +1,11 @@
 package radir
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 /*
@@ -373,6 +376,207 @@ func TestRegistration_Walk(t *testing.T) {
 	}
 }
 
+func TestRegistration_navigation(t *testing.T) {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X680().SetASN1Notation(`{iso(1)}`)
+	iso.Spatial().SetTopArc(iso.DN())
+
+	std := iso.NewChild(`0`, `standard`)
+	mbr := iso.NewChild(`2`, `member-body`)
+	org := iso.NewChild(`3`, `identified-organization`)
+	dod := org.NewChild(`6`, `dod`)
+
+	iso.SetXAxes(true)
+	(&Registrations{iso}).SetYAxes()
+	iso.Children().SetYAxes()
+
+	for idx, pair := range []struct {
+		funk func(...GetOrSetFunc) (*Registration, error)
+		want *Registration
+	}{
+		{mbr.Up, iso},
+		{dod.Top, iso},
+		{mbr.Left, std},
+		{mbr.Right, org},
+		{mbr.First, std},
+		{mbr.Last, org},
+		{dod.Up, org},
+	} {
+		if got, err := pair.funk(); err != nil {
+			t.Errorf("%s[%d] failed: %v", t.Name(), idx, err)
+			return
+		} else if got != pair.want {
+			t.Errorf("%s[%d] failed: want '%s', got '%s'",
+				t.Name(), idx, pair.want.DN(), got.DN())
+			return
+		}
+	}
+
+	if down, err := iso.Down(); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if down.Len() != 3 {
+		t.Errorf("%s failed: want 3 subArcs, got %d", t.Name(), down.Len())
+		return
+	}
+
+	// Reduce subArc values to raw number forms
+	org.Spatial().R_SubArc = []string{`6`}
+	if down, _ := org.Down(); down.Index(0) != dod {
+		t.Errorf("%s failed: number form subArc not resolved", t.Name())
+		return
+	}
+
+	// Collective fallback and loader use for
+	// an entry not found within the tree.
+	far := myDedicatedProfile.NewRegistration()
+	far.SetDN(`n=4,n=3,n=1,ou=Registrations,o=rA`)
+	dod.Spatial().R_TopArc = ``
+	dod.Spatial().RC_TopArc = far.DN()
+
+	if got, err := dod.Top(); err != nil || got != nil {
+		t.Errorf("%s failed: want nil w/o loader, got '%s' (%v)",
+			t.Name(), got.DN(), err)
+		return
+	}
+
+	loader := func(x ...any) (any, error) {
+		if x[0].(string) == far.DN() {
+			return far, nil
+		}
+		return nil, InvalidDNErr
+	}
+
+	if got, err := dod.Top(loader); err != nil || got != far {
+		t.Errorf("%s failed: loader not honored (%v)", t.Name(), err)
+		return
+	}
+
+	dod.Spatial().RC_TopArc = `n=5,n=3,n=1,ou=Registrations,o=rA`
+	if _, err := dod.Top(loader); err != InvalidDNErr {
+		t.Errorf("%s failed: want error, got %v", t.Name(), err)
+		return
+	}
+
+	var nilReg *Registration
+	if _, err := nilReg.Up(); err != NilRegistrationErr {
+		t.Errorf("%s failed: want error, got %v", t.Name(), err)
+		return
+	}
+}
+
+func TestRegistration_CheckSpatial(t *testing.T) {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X680().SetASN1Notation(`{iso(1)}`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	std := iso.NewChild(`0`, `standard`)
+	mbr := iso.NewChild(`2`, `member-body`)
+	dod := org.NewChild(`6`, `dod`)
+
+	// Repair an unlinked tree, then confirm
+	// it is deemed consistent afterwards.
+	std.CheckSpatial(true)
+	if faults := dod.CheckSpatial(); len(faults) > 0 {
+		t.Errorf("%s failed: unexpected faults after repair: %v", t.Name(), faults)
+		return
+	}
+
+	if iso.Children().Index(0) != std || dod.Spatial().TopArc() != iso.DN() {
+		t.Errorf("%s failed: repair did not sort or link tree", t.Name())
+		return
+	}
+
+	// Simulate drift after manual edits
+	mbr.Spatial().SetLeftArc(org.DN())
+	mbr.Spatial().SetMinArc(mbr.DN())
+	dod.Spatial().SetTopArc(org.DN())
+	org.Spatial().R_SubArc = nil
+	iso.Spatial().SetSubArc(`n=9,n=1,ou=Registrations,o=rA`)
+	std.Spatial().R_SubArc = []string{`6`}
+
+	want := map[string]SpatialFaultKind{
+		mbr.DN() + `:leftArc`: WrongSpatialArc,
+		mbr.DN() + `:minArc`:  StaleSpatialArc,
+		dod.DN() + `:topArc`:  NonRootTopArc,
+		iso.DN() + `:subArc`:  DanglingSpatialDN,
+		std.DN() + `:subArc`:  DanglingSpatialDN,
+	}
+
+	faults := iso.CheckSpatial(true)
+	if len(faults) != len(want) {
+		t.Errorf("%s failed: want %d faults, got %d: %v",
+			t.Name(), len(want), len(faults), faults)
+		return
+	}
+
+	for _, fault := range faults {
+		if kind := want[fault.DN+`:`+fault.Type]; kind != fault.Kind {
+			t.Errorf("%s failed: unexpected fault %s", t.Name(), fault)
+			return
+		}
+	}
+
+	if faults = iso.CheckSpatial(); len(faults) > 0 {
+		t.Errorf("%s failed: unexpected faults after repair: %v", t.Name(), faults)
+		return
+	}
+
+	// Confirm a subArc set lacking a child is reported.
+	iso.Spatial().R_SubArc = iso.Spatial().R_SubArc[1:]
+	if faults = iso.CheckSpatial(); len(faults) != 1 || faults[0].Kind != MissingSubArc {
+		t.Errorf("%s failed: missing subArc not reported: %v", t.Name(), faults)
+		return
+	}
+
+	// Confirm a partially loaded tree, whose root arc
+	// and uppermost parent are absent, bearing a sole
+	// child is deemed consistent.
+	sub := myDedicatedProfile.NewRegistration()
+	sub.SetDN(`n=3,n=1,ou=Registrations,o=rA`)
+	sub.X680().SetN(`3`)
+	sub.X680().SetDotNotation(`1.3`)
+	kid := sub.NewChild(`6`, `dod`)
+	sub.Spatial().SetSupArc(iso.DN())
+	sub.Spatial().SetTopArc(iso.DN())
+	sub.Spatial().SetSubArc(kid.DN())
+	kid.Spatial().SetSupArc(sub.DN())
+	kid.Spatial().SetMinArc(kid.DN())
+	kid.Spatial().SetMaxArc(kid.DN())
+	kid.Spatial().SetLeftArc(kid.DN())
+	kid.Spatial().SetRightArc(kid.DN())
+	kid.Spatial().SetTopArc(iso.DN())
+
+	if faults = kid.CheckSpatial(); len(faults) > 0 {
+		t.Errorf("%s failed: unexpected faults within subtree: %v", t.Name(), faults)
+		return
+	}
+
+	kid.Spatial().SetTopArc(sub.DN())
+	if faults = kid.CheckSpatial(); len(faults) != 1 || faults[0].Kind != NonRootTopArc || faults[0].Expect != iso.DN() {
+		t.Errorf("%s failed: wrong topArc not reported: %v", t.Name(), faults)
+		return
+	}
+
+	// Confirm a repaired subtree is deemed consistent.
+	kid.CheckSpatial(true)
+	if faults = kid.CheckSpatial(); len(faults) > 0 {
+		t.Errorf("%s failed: unexpected faults after subtree repair: %v", t.Name(), faults)
+		return
+	} else if top := kid.Spatial().TopArc(); top != iso.DN() {
+		t.Errorf("%s failed: want repaired topArc %s, got %s", t.Name(), iso.DN(), top)
+		return
+	}
+
+	var nilReg *Registration
+	_ = nilReg.CheckSpatial()
+	_ = SpatialFaultKind(0).String()
+}
+
 func TestITUXSeries_unmarshal(t *testing.T) {
 	w := &X690{r_root: new(registeredRoot)}
 	w.SetDotEncoding(`BgEr`)
@@ -547,6 +751,306 @@ func TestSpatial(t *testing.T) {
 	}
 }
 
+func TestRegistration_CheckSchema(t *testing.T) {
+	reg := myDedicatedProfile.NewRegistration()
+	reg.SetDN(`n=1,n=3,n=1,ou=Registrations,o=rA`)
+	reg.X680().SetN(`1`)
+	reg.X680().SetDotNotation(`1.3.1`)
+	reg.X660().SetUnicodeValue(`Example`)
+
+	if v := reg.CheckSchema(); len(v) > 0 {
+		t.Errorf("%s failed: unexpected violations: %v", t.Name(), v)
+		return
+	}
+
+	// A sub arc of depth three (3) must not be a rootArc
+	reg.R_SOC = `rootArc`
+	reg.R_OC = []string{`top`, `registration`, `rootArc`}
+	if v := reg.CheckSchema(); len(v) != 1 ||
+		v[0].Kind != WrongStructuralClass || v[0].Expect != `arc` {
+		t.Errorf("%s failed: want structural violation, got %v", t.Name(), v)
+		return
+	}
+
+	// Combined authority content requires the
+	// corresponding AUXILIARY class.
+	com := myCombinedProfile.NewRegistration()
+	com.SetDN(`n=1,ou=Registrations,o=rA`)
+	com.X680().SetN(`1`)
+	com.X660().CombinedFirstAuthority().SetCN(`Jesse Coretta`)
+	if v := com.CheckSchema(); len(v) > 0 {
+		t.Errorf("%s failed: unexpected combined violations: %v", t.Name(), v)
+		return
+	} else if com.refreshObjectClasses(); !strInSlice(`firstAuthorityContext`, com.ObjectClasses()) {
+		t.Errorf("%s failed: firstAuthorityContext not refreshed", t.Name())
+		return
+	}
+
+	want := map[SchemaViolationKind]string{
+		MissingMandatoryType: `n`,
+		DisallowedType:       `registrantID`,
+		MultipleSingleValues: `dotNotation`,
+		WrongStructuralClass: `arc, rootArc`,
+	}
+
+	v := CheckEntrySchema(map[string][]string{
+		`dn`:           {`n=1,ou=Registrations,o=rA`},
+		`objectClass`:  {`top`, `registration`, `arc`, `rootArc`, `x680Context`},
+		`dotNotation`:  {`1`, `1.3`},
+		`registrantID`: {`X`},
+	})
+	if len(v) != len(want) {
+		t.Errorf("%s failed: want %d violations, got %v", t.Name(), len(want), v)
+		return
+	}
+
+	for _, viol := range v {
+		if want[viol.Kind] != viol.Type {
+			t.Errorf("%s failed: unexpected violation: %s", t.Name(), viol)
+			return
+		}
+	}
+
+	// Every SINGLE-VALUE type must be a known RASCHEMA type.
+	for _, at := range SingleValueAttributeTypes {
+		_, fa := FirstAuthorityAttributeTypes[at]
+		_, ca := CurrentAuthorityAttributeTypes[at]
+		_, sp := SponsorAttributeTypes[at]
+		if attributeTypeOID(at) == `` && !(fa || ca || sp) {
+			t.Errorf("%s failed: unknown SINGLE-VALUE type %s", t.Name(), at)
+			return
+		}
+	}
+
+	var nilReg *Registration
+	if v = nilReg.CheckSchema(); len(v) > 0 {
+		t.Errorf("%s failed: nil receiver produced violations", t.Name())
+	}
+}
+
+func TestRegistration_StructureRule(t *testing.T) {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	org := iso.NewChild(`3`, `identified-organization`)
+
+	for idx, reg := range []*Registration{iso, org} {
+		if rule, err := reg.StructureRule(); err != nil {
+			t.Errorf("%s[%d] failed: %v", t.Name(), idx, err)
+			return
+		} else if rule.ID != idx+1 {
+			t.Errorf("%s[%d] failed: want rule %d, got '%s'", t.Name(), idx, idx+1, rule)
+			return
+		}
+	}
+
+	if rule, _ := org.StructureRule(); rule.String() !=
+		`( 2 NAME 'arcStructure' FORM nArcForm SUP ( 1 2 ) )` {
+		t.Errorf("%s failed: unexpected rule string '%s'", t.Name(), rule)
+		return
+	}
+
+	for idx, bad := range []struct {
+		dn, soc string
+		err     error
+	}{
+		{`n=1,ou=Registrations,o=rA`, `arc`, StructureRuleErr},
+		{`n=3,cn=X,ou=Registrations,o=rA`, `arc`, NameFormErr},
+		{`n=3+cn=X,n=1,ou=Registrations,o=rA`, `arc`, NameFormErr},
+		{`dotNotation=1.3,ou=Registrations,o=rA`, `arc`, NameFormErr},
+		{`n=X,n=1,ou=Registrations,o=rA`, `arc`, NameFormErr},
+		{`n=3,n=1,o=rA`, `arc`, InvalidDNErr},
+	} {
+		if _, err := myDedicatedProfile.StructureRule(bad.dn, bad.soc); err == nil ||
+			!hasPfx(err.Error(), bad.err.Error()) {
+			t.Errorf("%s[%d] failed: want '%v', got '%v'", t.Name(), idx, bad.err, err)
+			return
+		}
+	}
+
+	// RDN and governingStructureRule values must agree
+	org.R_GSR = `1`
+	if _, err := org.StructureRule(); err == nil {
+		t.Errorf("%s failed: expected governingStructureRule error", t.Name())
+		return
+	}
+	org.R_GSR = ``
+	org.X680().SetN(`4`)
+	if _, err := org.StructureRule(); err == nil {
+		t.Errorf("%s failed: expected RDN value error", t.Name())
+		return
+	}
+
+	twoD := &DITProfile{R_Settings: newProfileSettings()}
+	twoD.SetModel(TwoDimensional)
+	twoD.SetRegistrationBase(`ou=Registrations,o=rA`)
+	if rule, err := twoD.StructureRule(`dotNotation=1.3.6,ou=Registrations,o=rA`, `arc`); err != nil || rule.ID != 3 {
+		t.Errorf("%s failed: want 2D rule 3, got '%s' (%v)", t.Name(), rule, err)
+	} else if _, err = twoD.StructureRule(`n=6,n=3,n=1,ou=Registrations,o=rA`, `arc`); err == nil {
+		t.Errorf("%s failed: expected 2D name form error", t.Name())
+	}
+}
+
+func TestRegistration_TransferAuthority(t *testing.T) {
+	defer func(orig func() time.Time) { now = orig }(now)
+	now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	const ts = `20240301120000Z`
+
+	// Combined: the outgoing current authority becomes the first authority
+	reg := myCombinedProfile.NewRegistration(true)
+	reg.SetDN(`n=1,ou=Registrations,o=rA`)
+	reg.X660().CombinedCurrentAuthority().SetCN(`Old Authority`)
+	reg.X660().CombinedCurrentAuthority().SetStartTime(`20010101000000Z`)
+	reg.X660().CombinedSponsor().SetCN(`Sponsoring Org`)
+
+	next := myDedicatedProfile.NewRegistrant()
+	next.CurrentAuthority().SetCN(`New Authority`)
+
+	mods, err := reg.TransferAuthority(next)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	fa, ca, sp := reg.X660().CombinedFirstAuthority(), reg.X660().CombinedCurrentAuthority(), reg.X660().CombinedSponsor()
+	if fa.CN() != `Old Authority` || fa.StartTime() != `20010101000000Z` || fa.EndTime() != ts ||
+		ca.CN() != `New Authority` || ca.StartTime() != ts || sp.CN() != `Sponsoring Org` || sp.EndTime() != `` ||
+		!strInSlice(`firstAuthorityContext`, reg.ObjectClasses()) {
+		t.Errorf("%s failed: unexpected combined succession:\n%s", t.Name(), reg.LDIF(0))
+		return
+	}
+
+	want := map[string]uint{
+		`currentAuthorityCommonName`:     ReplaceModification,
+		`currentAuthorityStartTimestamp`: ReplaceModification,
+		`firstAuthorityCommonName`:       AddModification,
+		`firstAuthorityEndTimestamp`:     AddModification,
+		`objectClass`:                    AddModification,
+		`registrationModified`:           AddModification,
+	}
+	for _, mod := range mods {
+		if mod.Type == `sponsorEndTimestamp` {
+			t.Errorf("%s failed: sponsor modified: %s", t.Name(), mod)
+		} else if op, found := want[mod.Type]; found && op != mod.Operation {
+			t.Errorf("%s failed: unexpected modification %s", t.Name(), mod)
+		}
+		delete(want, mod.Type)
+	}
+	if len(want) > 0 {
+		t.Errorf("%s failed: missing modifications for %v", t.Name(), want)
+		return
+	}
+
+	// Dedicated: DN references move, and registrants are left alone
+	old := myDedicatedProfile.NewRegistrant()
+	old.SetDN(`registrantID=OLD,ou=Registrants,o=rA`)
+	old.CurrentAuthority().SetCN(`Old Authority`)
+	next.SetDN(`registrantID=NEW,ou=Registrants,o=rA`)
+
+	reg = myDedicatedProfile.NewRegistration()
+	reg.SetDN(`n=2,ou=Registrations,o=rA`)
+	reg.X660().SetCurrentAuthorities(old.DN())
+
+	if mods, err = reg.TransferAuthority(next); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if x := reg.X660(); x.FirstAuthorities()[0] != old.DN() ||
+		x.CurrentAuthorities()[0] != next.DN() || len(x.CurrentAuthorities()) != 1 {
+		t.Errorf("%s failed: DN references not updated:\n%s", t.Name(), reg.LDIF(0))
+		return
+	} else if !old.R_FA.IsZero() || next.CurrentAuthority().StartTime() != `` {
+		t.Errorf("%s failed: shared registrants modified", t.Name())
+		return
+	}
+	for _, mod := range mods {
+		if mod.DN != reg.DN() {
+			t.Errorf("%s failed: unexpected modification %s", t.Name(), mod)
+			return
+		}
+	}
+
+	if _, err = reg.TransferAuthority(next); err == nil {
+		t.Errorf("%s failed: expected error for redundant transfer", t.Name())
+		return
+	}
+
+	third := myDedicatedProfile.NewRegistrant()
+	third.SetDN(`registrantID=THIRD,ou=Registrants,o=rA`)
+	if _, err = reg.TransferAuthority(third); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if x := reg.X660(); x.FirstAuthorities()[0] != old.DN() ||
+		x.Sponsors()[0] != next.DN() || x.CurrentAuthorities()[0] != third.DN() {
+		t.Errorf("%s failed: DN references not updated:\n%s", t.Name(), reg.LDIF(0))
+		return
+	} else if _, err = reg.TransferAuthority(old); !errors.Is(err, RegistrantPolicyErr) {
+		t.Errorf("%s failed: expected RegistrantPolicyErr, got %v", t.Name(), err)
+		return
+	}
+
+	// Combined: a recorded first authority is left alone, and
+	// the outgoing current authority becomes the sponsor.
+	reg = myCombinedProfile.NewRegistration(true)
+	reg.SetDN(`n=3,ou=Registrations,o=rA`)
+	reg.X660().CombinedFirstAuthority().SetCN(`Founder`)
+	reg.X660().CombinedCurrentAuthority().SetCN(`Old Authority`)
+	reg.X660().CombinedCurrentAuthority().SetStartTime(`20010101000000Z`)
+
+	if _, err = reg.TransferAuthority(next); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if fa, sp = reg.X660().CombinedFirstAuthority(), reg.X660().CombinedSponsor(); fa.CN() != `Founder` ||
+		fa.EndTime() != `` || sp.CN() != `Old Authority` || sp.StartTime() != `20010101000000Z` || sp.EndTime() != ts {
+		t.Errorf("%s failed: unexpected sponsor succession:\n%s", t.Name(), reg.LDIF(0))
+		return
+	}
+
+	// With no vacant role, the outgoing authority cannot be retained.
+	if _, err = reg.TransferAuthority(next); !errors.Is(err, RegistrantPolicyErr) {
+		t.Errorf("%s failed: expected RegistrantPolicyErr, got %v", t.Name(), err)
+	} else if reg.X660().CombinedCurrentAuthority().CN() != `New Authority` {
+		t.Errorf("%s failed: receiver modified despite error", t.Name())
+	}
+}
+
+func TestRegistration_AuthoritiesAt(t *testing.T) {
+	root := myCombinedProfile.NewRegistration(true)
+	root.SetDN(`n=1,ou=Registrations,o=rA`)
+	root.X680().SetN(`1`)
+	root.X660().CombinedFirstAuthority().SetCN(`Founder`)
+	root.X660().CombinedFirstAuthority().SetStartTime(`19900101000000Z`)
+	root.X660().CombinedFirstAuthority().SetEndTime(`20050101000000Z`)
+	root.X660().CombinedCurrentAuthority().SetCN(`Successor`)
+	root.X660().CombinedCurrentAuthority().SetStartTime(`20050101000000Z`)
+
+	child := root.NewChild(`3`, `identified-organization`)
+	child.X660().CombinedSponsor().SetCN(`Sponsoring Org`)
+	child.X660().CombinedSponsor().SetStartTime(`20000101000000Z`)
+	child.X660().CombinedSponsor().SetEndTime(`20100101000000Z`)
+
+	when := time.Date(2003, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := root.AuthoritiesAt(when, nil); len(got) != 1 || got[0].Name() != `Founder` {
+		t.Errorf("%s failed: unexpected authorities in 2003: %v", t.Name(), got)
+		return
+	} else if got = root.AuthoritiesAt(when.AddDate(5, 0, 0), nil, CurrentAuthorityRole); len(got) != 1 || got[0].Name() != `Successor` {
+		t.Errorf("%s failed: unexpected authorities in 2008: %v", t.Name(), got)
+		return
+	} else if got = child.AuthoritiesAt(when, nil, SponsorRole); len(got) != 1 || got[0].Name() != `Sponsoring Org` {
+		t.Errorf("%s failed: unexpected sponsor in 2003: %v", t.Name(), got)
+		return
+	}
+
+	var names []string
+	for _, event := range child.AuthorityTimeline(nil) {
+		names = append(names, event.Tenure.Name())
+	}
+
+	want := []string{`Founder`, `Sponsoring Org`, `Founder`, `Successor`, `Sponsoring Org`}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("%s failed:\nwant: %v\ngot:  %v", t.Name(), want, names)
+	}
+}
+
 func TestRegistration_codecov(t *testing.T) {
 	if err := bogusRegistration_codecov(); err != nil {
 		t.Errorf("%s failed: %v", t.Name(), err)
@@ -1052,3 +1556,111 @@ func testBogusRegistrationSetters(nilReg *Registration) error {
 
 	return nil
 }
+
+func TestRegistration_Promote(t *testing.T) {
+	staging := &DITProfile{R_Settings: newProfileSettings(), R_DN: `cn=Staging,o=rA`}
+	staging.SetModel(ThreeDimensional)
+	staging.SetRegistrationBase(`ou=Registrations,ou=Staging,o=rA`)
+	staging.SetRegistrantBase(`ou=Registrants,ou=Staging,o=rA`)
+
+	active := &DITProfile{R_Settings: newProfileSettings(), R_DN: `cn=Active,o=rA`}
+	active.SetModel(ThreeDimensional)
+	active.SetRegistrationBase(`ou=Registrations,o=rA`)
+	active.SetRegistrantBase(`ou=Registrants,o=rA`)
+
+	ant := staging.NewRegistrant()
+	ant.SetDN(`registrantID=X,ou=Registrants,ou=Staging,o=rA`)
+	ant.CurrentAuthority().SetCN(`Authority`)
+
+	root := staging.NewRegistration(true)
+	root.SetDN(`n=1,ou=Registrations,ou=Staging,o=rA`)
+	root.X680().SetN(`1`)
+	root.X680().SetIdentifier(`iso`)
+	root.X660().SetCurrentAuthorities(ant.DN())
+	root.SetSeeAlso(`n=2,ou=Registrations,ou=Staging,o=rA`)
+	root.SetSeeAlso(`cn=Elsewhere,o=rA`)
+	child := root.NewChild(`3`, `identified-organization`)
+	se := root.NewSubentry(`Collective`)
+
+	if _, err := root.Promote(active, false, nil); err == nil {
+		t.Errorf("%s failed: expected error for subordinates", t.Name())
+		return
+	} else if _, err = root.Promote(staging, true, nil); err == nil {
+		t.Errorf("%s failed: expected error for identical profiles", t.Name())
+		return
+	}
+
+	// Staging authorities must be supplied, lest they dangle.
+	if _, err := root.Promote(active, true, nil); !errors.Is(err, RegistrantValidityErr) {
+		t.Errorf("%s failed: expected RegistrantValidityErr, got %v", t.Name(), err)
+		return
+	} else if root.DN() != `n=1,ou=Registrations,ou=Staging,o=rA` || root.X660().CurrentAuthorities()[0] != ant.DN() {
+		t.Errorf("%s failed: receiver modified despite error", t.Name())
+		return
+	}
+
+	promo, err := root.Promote(active, true, Registrants{ant})
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	if root.DN() != `n=1,ou=Registrations,o=rA` || child.DN() != `n=3,n=1,ou=Registrations,o=rA` ||
+		se.DN() != `cn=Collective,n=1,ou=Registrations,o=rA` || root.Profile() != active {
+		t.Errorf("%s failed: DNs not rebased: %s, %s, %s", t.Name(), root.DN(), child.DN(), se.DN())
+		return
+	} else if ca := root.X660().CurrentAuthorities(); len(ca) != 1 || ca[0] != `registrantID=X,ou=Registrants,o=rA` {
+		t.Errorf("%s failed: authority not rebased: %v", t.Name(), ca)
+		return
+	} else if also := root.SeeAlso(); also[0] != `n=2,ou=Registrations,o=rA` || also[1] != `cn=Elsewhere,o=rA` {
+		t.Errorf("%s failed: seeAlso not rebased: %v", t.Name(), also)
+		return
+	}
+
+	var adds []string
+	for _, entry := range promo.Adds {
+		adds = append(adds, entry[`dn`][0])
+	}
+	wantAdds := []string{`registrantID=X,ou=Registrants,o=rA`, root.DN(), se.DN(), child.DN()}
+	wantDels := []string{
+		`n=3,n=1,ou=Registrations,ou=Staging,o=rA`,
+		`cn=Collective,n=1,ou=Registrations,ou=Staging,o=rA`,
+		`n=1,ou=Registrations,ou=Staging,o=rA`,
+	}
+	if fmt.Sprint(adds) != fmt.Sprint(wantAdds) || fmt.Sprint(promo.Deletes) != fmt.Sprint(wantDels) {
+		t.Errorf("%s failed:\nadds: %v\ndels: %v", t.Name(), adds, promo.Deletes)
+		return
+	} else if ant.DN() != `registrantID=X,ou=Registrants,ou=Staging,o=rA` {
+		t.Errorf("%s failed: staging registrant modified", t.Name())
+		return
+	} else if l := promo.LDIF(); !strings.Contains(l, "dn: n=1,ou=Registrations,o=rA\nchangetype: add\nobjectClass: ") ||
+		!strings.Contains(l, "dn: n=1,ou=Registrations,ou=Staging,o=rA\nchangetype: delete\n") {
+		t.Errorf("%s failed: unexpected LDIF:\n%s", t.Name(), l)
+		return
+	}
+
+	// rejection
+	bad := staging.NewRegistration(true)
+	bad.SetDN(`n=2,ou=Registrations,ou=Staging,o=rA`)
+	if _, err = bad.Reject(` `); err == nil {
+		t.Errorf("%s failed: expected error for empty reason", t.Name())
+		return
+	}
+
+	mods, err := bad.Reject(`Duplicate of n=1`)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if info := bad.Supplement().Info(); len(info) != 1 || info[0] != `Duplicate of n=1` {
+		t.Errorf("%s failed: reason not recorded: %v", t.Name(), info)
+		return
+	}
+
+	var found bool
+	for _, mod := range mods {
+		found = found || (mod.Type == `registrationInformation` && mod.Operation == AddModification)
+	}
+	if !found {
+		t.Errorf("%s failed: missing registrationInformation modification: %v", t.Name(), mods)
+	}
+}
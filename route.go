@@ -0,0 +1,200 @@
+package radir
+
+/*
+route.go contains the means of resolving the *DITProfile which governs
+a given DN or numeric OID.
+*/
+
+/*
+oidRoute is a single OID prefix routing rule of a *[DITProfile].
+*/
+type oidRoute struct {
+	prefix []string // dotNotation arcs
+	base   int      // registration base index
+}
+
+/*
+SetOIDRoute assigns an OID prefix routing rule to the receiver instance,
+whereby registrations whose numeric OID equals, or descends from, the input
+dotNotation prefix are to reside beneath the Nth registration base.
+
+Reassigning a prefix already routed replaces its base index. An error is
+returned if the prefix is not a valid numeric OID (or root arc), or if the
+base index is not present within the receiver.
+
+See also [DUAConfig.GoverningProfileByOID].
+*/
+func (r *DITProfile) SetOIDRoute(prefix string, base int) (err error) {
+	if r.IsZero() {
+		err = DUAConfigValidityErr
+		return
+	} else if !IsNumericOID(prefix) && !strInSlice(prefix, []string{`0`, `1`, `2`}) {
+		err = InvalidOIDErr
+		return
+	} else if base < 0 || base >= r.NumRegistrationBase() {
+		err = wrapErr(DUAConfigValidityErr, "no registration base at index "+itoa(base))
+		return
+	}
+
+	arcs := dotSplit(prefix)
+	for i := 0; i < len(r.r_routes); i++ {
+		if join(r.r_routes[i].prefix, `.`) == prefix {
+			r.r_routes[i].base = base
+			return
+		}
+	}
+
+	r.r_routes = append(r.r_routes, oidRoute{prefix: arcs, base: base})
+
+	return
+}
+
+/*
+OIDRoute returns the registration base index to which the input dotNotation
+value is routed by the receiver instance, alongside the number of arcs of the
+matching prefix. Where several prefixes match, the longest prevails.
+
+A base index of -1 and a length of zero are returned if no prefix matches.
+*/
+func (r *DITProfile) OIDRoute(dot string) (base, length int) {
+	base = -1
+	if r.IsZero() || len(dot) == 0 {
+		return
+	}
+
+	arcs := dotSplit(dot)
+	for _, route := range r.r_routes {
+		if len(route.prefix) <= length || len(route.prefix) > len(arcs) {
+			continue
+		}
+
+		match := true
+		for i := 0; i < len(route.prefix) && match; i++ {
+			match = route.prefix[i] == arcs[i]
+		}
+
+		if match {
+			base, length = route.base, len(route.prefix)
+		}
+	}
+
+	return
+}
+
+/*
+profiles returns all non-nil *[DITProfile] instances of the receiver: the
+"Profiles" instances if any are present, else the "DSE" instance alone.
+*/
+func (r *DUAConfig) profiles() (profs []*DITProfile) {
+	if r == nil {
+		return
+	}
+
+	for _, prof := range r.R_Profiles {
+		if !prof.IsZero() {
+			profs = append(profs, prof)
+		}
+	}
+
+	if len(profs) == 0 && !r.R_DSE.IsZero() {
+		profs = append(profs, r.R_DSE)
+	}
+
+	return
+}
+
+/*
+GoverningProfile returns the *[DITProfile] instance which governs the input
+DN, alongside the index of the matching base and a Boolean value indicative
+of whether the base is a registrant base.
+
+The governing profile is that bearing the longest registration or registrant
+base of which the input DN is either a descendant, or is itself. Registration
+bases prevail over registrant bases of equal length -- as is the case under the
+terms of the "Combined Registrants Policy" -- while earlier profiles prevail
+over later ones in the event of a tie.
+
+The matching base is selected within the profile by way of [DITProfile.RegistrationTarget]
+or [DITProfile.RegistrantTarget], such that subsequent calls of [DITProfile.RegistrationBase]
+or [DITProfile.RegistrantBase] return that base.
+
+Case is not significant in the matching process, nor is whitespace surrounding
+RDN separators. A nil profile and a base index of -1 are returned if no base
+matches.
+*/
+func (r *DUAConfig) GoverningProfile(dn string) (prof *DITProfile, base int, registrant bool) {
+	base = -1
+
+	rdns := rdnSequence(dn)
+	if len(rdns) == 0 {
+		return
+	}
+
+	var longest int
+	for _, p := range r.profiles() {
+		for t, bases := range [][]string{p.R_RegBase, p.R_AthyBase} {
+			for i, b := range bases {
+				sfx := rdnSequence(b)
+				if len(sfx) > longest && rdnSuffixEqual(rdns, sfx) {
+					longest = len(sfx)
+					prof, base, registrant = p, i, t == 1
+				}
+			}
+		}
+	}
+
+	if registrant {
+		prof.RegistrantTarget(base)
+	} else if prof != nil {
+		prof.RegistrationTarget(base)
+	}
+
+	return
+}
+
+/*
+GoverningProfileByOID returns the *[DITProfile] instance which governs the
+registration bearing the input dotNotation value, alongside the index of the
+registration base beneath which such a registration is to reside.
+
+The governing profile is that bearing the longest OID prefix routing rule
+matching the input value, as assigned through [DITProfile.SetOIDRoute]. Earlier
+profiles prevail over later ones in the event of a tie. If no rule matches, and
+the receiver bears a sole profile, that profile is returned alongside its
+currently selected registration base.
+
+The matching base is selected within the profile by way of [DITProfile.RegistrationTarget],
+such that subsequent calls of [DITProfile.RegistrationBase] return that base.
+
+A nil profile and a base index of -1 are returned if the input value is not a
+valid numeric OID, or if it cannot be routed.
+*/
+func (r *DUAConfig) GoverningProfileByOID(dot string) (prof *DITProfile, base int) {
+	base = -1
+	if !IsNumericOID(dot) && !strInSlice(dot, []string{`0`, `1`, `2`}) {
+		return
+	}
+
+	profs := r.profiles()
+
+	var longest int
+	for _, p := range profs {
+		if idx, length := p.OIDRoute(dot); length > longest {
+			longest = length
+			prof, base = p, idx
+		}
+	}
+
+	if prof == nil && len(profs) == 1 && profs[0].NumRegistrationBase() > 0 {
+		prof = profs[0]
+		if base = prof.r_bsel[0]; base >= prof.NumRegistrationBase() {
+			base = 0
+		}
+	}
+
+	if prof != nil {
+		prof.RegistrationTarget(base)
+	}
+
+	return
+}
@@ -0,0 +1,742 @@
+package radir
+
+/*
+schema.go contains RASCHEMA object class definitions and the means of
+checking entries against them.
+*/
+
+/*
+SchemaViolationKind describes the nature of a single schema violation
+reported by [CheckEntrySchema] and the various CheckSchema methods.
+*/
+type SchemaViolationKind uint8
+
+const (
+	_                    SchemaViolationKind = iota
+	MissingMandatoryType                     // a MUST type required by one or more classes is absent
+	DisallowedType                           // type is not permitted by any class present
+	MultipleSingleValues                     // SINGLE-VALUE type bears more than one value
+	WrongStructuralClass                     // structural class is absent, ambiguous or inappropriate
+)
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r SchemaViolationKind) String() (s string) {
+	switch r {
+	case MissingMandatoryType:
+		s = `missing mandatory type`
+	case DisallowedType:
+		s = `disallowed type`
+	case MultipleSingleValues:
+		s = `multiple single values`
+	case WrongStructuralClass:
+		s = `wrong structural class`
+	}
+
+	return
+}
+
+/*
+SchemaViolation describes a single disagreement between the content of an
+entry and the RASCHEMA object class definitions it references.
+*/
+type SchemaViolation struct {
+	DN     string              // DN of the offending entry
+	Type   string              // attribute type or object class concerned
+	Expect string              // expected structural class, if applicable
+	Kind   SchemaViolationKind // nature of the violation
+}
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r SchemaViolation) String() (s string) {
+	s = sprintf("%s: %s %s", r.DN, r.Kind, r.Type)
+	if r.Expect != "" {
+		s += sprintf(" (want '%s')", r.Expect)
+	}
+
+	return
+}
+
+const (
+	abstractClass uint8 = iota
+	structuralClass
+	auxiliaryClass
+)
+
+/*
+objectClassDef is a minimal abstraction of an [objectClass] definition,
+sufficient for the purpose of checking entry content.
+
+[objectClass]: https://www.rfc-editor.org/rfc/rfc4512.html#section-4.1.1
+*/
+type objectClassDef struct {
+	kind uint8
+	sup  string
+	must []string
+	may  []string
+}
+
+/*
+schemaClasses contains the object classes defined in [Section 2.5 of the
+RASCHEMA I-D], as well as those standard classes upon which entries
+produced by this package rely.
+
+[Section 2.5 of the RASCHEMA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5
+*/
+var schemaClasses map[string]objectClassDef = map[string]objectClassDef{
+	// RFC 4512 § 2.4.1, RFC 3672 § 2.4, RFC 3671 § 2.1, RFC 4512 § 4.3
+	`top`:                         {kind: abstractClass, must: []string{`objectClass`}},
+	`subentry`:                    {kind: structuralClass, sup: `top`, must: []string{`cn`, `subtreeSpecification`}},
+	`collectiveAttributeSubentry`: {kind: auxiliaryClass},
+	`extensibleObject`:            {kind: auxiliaryClass, sup: `top`},
+
+	// RASCHEMA § 2.5.1 - 2.5.3
+	`registration`: {kind: abstractClass, sup: `top`, must: []string{`n`},
+		may: []string{`description`, `seeAlso`, `rATTL`}},
+	`rootArc`: {kind: structuralClass, sup: `registration`},
+	`arc`:     {kind: structuralClass, sup: `registration`, may: []string{`longArc`}},
+
+	// RASCHEMA § 2.5.4 - 2.5.7
+	`x660Context`: {kind: auxiliaryClass, sup: `registration`,
+		may: []string{`additionalUnicodeValue`, `currentAuthority`, `firstAuthority`,
+			`secondaryIdentifier`, `sponsor`, `standardizedNameForm`, `unicodeValue`}},
+	`x667Context`: {kind: auxiliaryClass, sup: `registration`, must: []string{`registeredUUID`}},
+	`x680Context`: {kind: auxiliaryClass, sup: `registration`,
+		may: []string{`aSN1Notation`, `dotNotation`, `identifier`, `iRI`,
+			`nameAndNumberForm`}},
+	`x690Context`: {kind: auxiliaryClass, sup: `registration`, may: []string{`dotEncoding`}},
+
+	// RASCHEMA § 2.5.8 - 2.5.12
+	`iTUTRegistration`:         {kind: auxiliaryClass, sup: `registration`},
+	`iSORegistration`:          {kind: auxiliaryClass, sup: `registration`},
+	`jointISOITUTRegistration`: {kind: auxiliaryClass, sup: `registration`},
+	`spatialContext`: {kind: auxiliaryClass, sup: `registration`,
+		may: []string{`leftArc`, `maxArc`, `minArc`, `rightArc`, `subArc`,
+			`supArc`, `topArc`}},
+	`registrationSupplement`: {kind: auxiliaryClass, sup: `registration`,
+		may: []string{`discloseTo`, `isFrozen`, `isLeafNode`,
+			`registrationClassification`, `registrationCreated`,
+			`registrationInformation`, `registrationModified`,
+			`registrationRange`, `registrationStatus`, `registrationURI`}},
+
+	// RASCHEMA § 2.5.13 - 2.5.17
+	`firstAuthorityContext`:   {kind: auxiliaryClass, sup: `top`, may: mapKeys(FirstAuthorityAttributeTypes)},
+	`currentAuthorityContext`: {kind: auxiliaryClass, sup: `top`, may: mapKeys(CurrentAuthorityAttributeTypes)},
+	`sponsorContext`:          {kind: auxiliaryClass, sup: `top`, may: mapKeys(SponsorAttributeTypes)},
+	`registrant`: {kind: structuralClass, sup: `top`, must: []string{`registrantID`},
+		may: []string{`description`, `seeAlso`, `rATTL`}},
+	`rADUAConfig`: {kind: auxiliaryClass, sup: `top`,
+		may: []string{`rADITProfile`, `rADirectoryModel`, `rARegistrantBase`,
+			`rARegistrationBase`, `rAServiceMail`, `rAServiceURI`, `rATTL`}},
+}
+
+/*
+operationalTypes contains the operational attribute types that may appear
+within entries regardless of object class.
+*/
+var operationalTypes []string = []string{
+	`collectiveAttributeSubentries`,
+	`collectiveExclusions`,
+	`governingStructureRule`,
+	`structuralObjectClass`,
+}
+
+/*
+singleValuedTypes contains the descriptors of all SINGLE-VALUE attribute
+types known to this package, namely those of [SingleValueAttributeTypes]
+alongside those SINGLE-VALUE operational types defined within [RFC 4512].
+
+[RFC 4512]: https://www.rfc-editor.org/rfc/rfc4512.html#section-3.4
+*/
+var singleValuedTypes []string = append([]string{
+	`governingStructureRule`,
+	`structuralObjectClass`,
+}, SingleValueAttributeTypes...)
+
+func mapKeys(m map[string]string) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sortStrs(keys)
+
+	return
+}
+
+/*
+CheckEntrySchema returns zero or more instances of [SchemaViolation], each
+of which describes an instance of disagreement between the input entry and
+the object classes it references. The input entry is expected to be of the
+form returned by the various Unmarshal methods, e.g.: [Registration.Unmarshal].
+
+The following conditions are reported:
+
+  - A MUST type of any class (or superclass) present is absent
+  - A type is not permitted by any class (or superclass) present
+  - A SINGLE-VALUE type bears more than one value
+  - The structural class is absent, ambiguous or differs from the "[structuralObjectClass]" value
+
+The optional structural input value(s) declare the structural class(es) that
+are acceptable for the entry. If the structural class of the entry is not
+among them, this is also reported.
+
+Operational and collective types are always permitted, as the latter may be
+virtual. Disallowed types are not reported if the entry bears the "[extensibleObject]"
+class, or any class unknown to this package, as the permitted types cannot be
+reliably determined in such cases.
+
+[structuralObjectClass]: https://www.rfc-editor.org/rfc/rfc4512.html#section-3.4.5
+[extensibleObject]: https://www.rfc-editor.org/rfc/rfc4512.html#section-4.3
+*/
+func CheckEntrySchema(entry map[string][]string, structural ...string) (violations []SchemaViolation) {
+	var dn string
+	if dns := entry[`dn`]; len(dns) > 0 {
+		dn = dns[0]
+	}
+
+	violate := func(typ, expect string, kind SchemaViolationKind) {
+		violations = append(violations, SchemaViolation{
+			DN:     dn,
+			Type:   typ,
+			Expect: expect,
+			Kind:   kind,
+		})
+	}
+
+	var names []string
+	for k := range entry {
+		if k != `dn` {
+			names = append(names, k)
+		}
+	}
+	sortStrs(names)
+
+	values := func(at string) (vals []string) {
+		for _, k := range names {
+			if eq(split(k, `;`)[0], at) {
+				vals = append(vals, entry[k]...)
+			}
+		}
+		return
+	}
+
+	must, may, socs, open := schemaClosure(values(`objectClass`))
+
+	for _, at := range must {
+		if len(values(at)) == 0 {
+			violate(at, ``, MissingMandatoryType)
+		}
+	}
+
+	for _, k := range names {
+		at := split(k, `;`)[0]
+		if !open && !strInSlice(at, must) && !strInSlice(at, may) &&
+			!strInSlice(at, operationalTypes) && !hasPfx(lc(at), `c-`) {
+			violate(at, ``, DisallowedType)
+		}
+		if strInSlice(at, singleValuedTypes) && len(entry[k]) > 1 {
+			violate(at, ``, MultipleSingleValues)
+		}
+	}
+
+	checkStructuralClass(socs, values(`structuralObjectClass`), structural, violate)
+
+	return
+}
+
+/*
+schemaClosure returns the MUST and MAY types, as well as the structural
+classes, derived from the input object classes and their superclasses. The
+Boolean return value indicates whether the permitted types are open-ended.
+*/
+func schemaClosure(ocs []string) (must, may, socs []string, open bool) {
+	var seen []string
+	for i := 0; i < len(ocs); i++ {
+		var oc string
+		for name := range schemaClasses {
+			if eq(name, ocs[i]) || ObjectClasses[name] == ocs[i] {
+				oc = name
+				break
+			}
+		}
+
+		if oc == `` {
+			open = true
+			continue
+		} else if strInSlice(oc, seen) {
+			continue
+		}
+		seen = append(seen, oc)
+
+		def := schemaClasses[oc]
+		open = open || oc == `extensibleObject`
+		if def.kind == structuralClass {
+			socs = append(socs, oc)
+		}
+		if def.sup != `` {
+			ocs = append(ocs, def.sup)
+		}
+		for _, at := range def.must {
+			if !strInSlice(at, must) {
+				must = append(must, at)
+			}
+		}
+		may = append(may, def.may...)
+	}
+
+	return
+}
+
+func checkStructuralClass(socs, declared, structural []string, violate func(string, string, SchemaViolationKind)) {
+	var want string
+	if len(structural) > 0 {
+		want = join(structural, ` or `)
+	}
+
+	switch len(socs) {
+	case 0:
+		violate(`objectClass`, want, WrongStructuralClass)
+		return
+	case 1:
+	default:
+		// Structural classes may only be chained
+		// by inheritance, which no class we know
+		// of does, so more than one is ambiguous.
+		violate(join(socs, `, `), want, WrongStructuralClass)
+		return
+	}
+
+	if len(declared) > 0 && !eq(declared[0], socs[0]) {
+		violate(declared[0], socs[0], WrongStructuralClass)
+	}
+
+	if want != `` && !strInSlice(socs[0], structural) {
+		violate(socs[0], want, WrongStructuralClass)
+	}
+}
+
+/*
+CheckSchema returns zero or more instances of [SchemaViolation] following
+an examination of the receiver instance against the RASCHEMA object class
+definitions. The "[objectClass]" values are assessed as they would appear
+following a refresh, but the receiver is not modified in this regard.
+
+If the root and depth of the receiver are known, the structural class
+is expected to be "[rootArc]" or "[arc]" accordingly. See [CheckEntrySchema]
+for details on the conditions reported.
+
+[objectClass]: https://www.rfc-editor.org/rfc/rfc4512.html#section-3.3
+[rootArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5.2
+[arc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5.3
+*/
+func (r *Registration) CheckSchema() (violations []SchemaViolation) {
+	if r.IsZero() {
+		return
+	}
+
+	entry := r.Unmarshal()
+	entry[`objectClass`] = r.effectiveObjectClasses()
+	violations = CheckEntrySchema(entry, r.expectedStructuralClass()...)
+
+	return
+}
+
+/*
+expectedStructuralClass returns the structural class appropriate for the
+receiver, if it can be determined.
+*/
+func (r *Registration) expectedStructuralClass() (soc []string) {
+	if rr := r.r_root; rr != nil && rr.Structural != `` {
+		soc = []string{rr.Structural}
+	} else if x := r.R_X680; !x.IsZero() {
+		switch depth := len(dotSplit(x.R_DotNot)); {
+		case depth == 1:
+			soc = []string{`rootArc`}
+		case depth > 1:
+			soc = []string{`arc`}
+		}
+	}
+
+	if len(soc) == 0 {
+		soc = []string{`rootArc`, `arc`}
+	}
+
+	return
+}
+
+/*
+CheckSchema returns zero or more instances of [SchemaViolation] following
+an examination of the receiver instance against the RASCHEMA object class
+definitions. The structural class is expected to be "[registrant]".
+
+See [CheckEntrySchema] for details on the conditions reported.
+
+[registrant]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5.16
+*/
+func (r *Registrant) CheckSchema() (violations []SchemaViolation) {
+	if r.IsZero() {
+		return
+	}
+
+	entry := make(map[string][]string)
+	for _, inner := range []map[string][]string{
+		unmarshalStruct(r, entry),
+		r.R_FA.unmarshal(),
+		r.R_CA.unmarshal(),
+		r.R_SA.unmarshal(),
+	} {
+		for k, v := range inner {
+			entry[k] = v
+		}
+	}
+
+	entry[`objectClass`] = r.effectiveObjectClasses()
+	violations = CheckEntrySchema(entry, `registrant`)
+
+	return
+}
+
+/*
+effectiveObjectClasses returns the objectClass values of the receiver as
+they would appear following a call of refreshObjectClasses, but without
+modifying any component of the receiver.
+*/
+func (r *Registrant) effectiveObjectClasses() (oc []string) {
+	oc = append(oc, r.R_OC...)
+	for _, pair := range []struct {
+		tag   string
+		empty bool
+	}{
+		{`firstAuthorityContext`, r.R_FA.IsZero() || r.R_FA.isEmpty()},
+		{`currentAuthorityContext`, r.R_CA.IsZero() || r.R_CA.isEmpty()},
+		{`sponsorContext`, r.R_SA.IsZero() || r.R_SA.isEmpty()},
+	} {
+		if pair.empty {
+			oc = removeStrInSlice(pair.tag, oc)
+		} else if !strInSlice(pair.tag, oc) {
+			oc = append(oc, pair.tag)
+		}
+	}
+
+	return
+}
+
+/*
+CheckSchema returns zero or more instances of [SchemaViolation] following
+an examination of the receiver instance against the definitions of the
+"[subentry]" and "[collectiveAttributeSubentry]" classes.
+
+See [CheckEntrySchema] for details on the conditions reported.
+
+[subentry]: https://www.rfc-editor.org/rfc/rfc3672.html#section-2.4
+[collectiveAttributeSubentry]: https://www.rfc-editor.org/rfc/rfc3671.html#section-2.1
+*/
+func (r *Subentry) CheckSchema() (violations []SchemaViolation) {
+	if !r.IsZero() {
+		violations = CheckEntrySchema(r.Unmarshal(), `subentry`)
+	}
+
+	return
+}
+
+/*
+CheckSchema returns zero or more instances of [SchemaViolation] following
+an examination of the receiver instance against the definition of the
+"[rADUAConfig]" class. No structural class is expected of an instance
+which abstracts the Root DSE, i.e.: one which bears no DN.
+
+See [CheckEntrySchema] for details on the conditions reported.
+
+[rADUAConfig]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5.17
+*/
+func (r *DITProfile) CheckSchema() (violations []SchemaViolation) {
+	if r.IsZero() {
+		return
+	}
+
+	entry := unmarshalStruct(r, make(map[string][]string))
+	if r.R_DN == `` {
+		// The Root DSE bears no structural class,
+		// so only consider the types themselves.
+		entry[`objectClass`] = append([]string{`rADUAConfig`}, r.R_OC...)
+		for _, v := range CheckEntrySchema(entry) {
+			if v.Kind != WrongStructuralClass {
+				violations = append(violations, v)
+			}
+		}
+	} else {
+		violations = CheckEntrySchema(entry)
+	}
+
+	return
+}
+
+/*
+nameFormDef is a minimal abstraction of a [nameForm] definition. The only
+name forms defined by the RASCHEMA I-D each bear a single MUST type and no
+MAY types. Note that "dotNotationForm", which names all registrations of
+the two dimensional model, is applicable to both "rootArc" and "arc".
+
+[nameForm]: https://www.rfc-editor.org/rfc/rfc4512.html#section-4.1.7.2
+*/
+type nameFormDef struct {
+	oc   []string
+	must string
+}
+
+/*
+nameForms contains the name forms defined in [Section 2.7 of the RASCHEMA
+I-D], keyed by the same descriptors used by [NameForms].
+
+[Section 2.7 of the RASCHEMA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.7
+*/
+var nameForms map[string]nameFormDef = map[string]nameFormDef{
+	`nRootArcForm`:    {oc: []string{`rootArc`}, must: `n`},
+	`nArcForm`:        {oc: []string{`arc`}, must: `n`},
+	`dotNotationForm`: {oc: []string{`rootArc`, `arc`}, must: `dotNotation`},
+}
+
+/*
+DITStructureRule implements an abstraction of a "[dITStructureRule]"
+definition.
+
+The rule identifier of the instance governing an entry is the value that
+entry should receive as its "[governingStructureRule]".
+
+[dITStructureRule]: https://www.rfc-editor.org/rfc/rfc4512.html#section-4.1.7.1
+[governingStructureRule]: https://www.rfc-editor.org/rfc/rfc4512.html#section-3.4.6
+*/
+type DITStructureRule struct {
+	ID       int    // rule identifier
+	Name     string // rule descriptor
+	Form     string // name form descriptor, e.g.: "nArcForm"
+	Superior []int  // superior rule identifiers; none if subordinate to a registration base
+}
+
+/*
+DITStructureRules contains the "[dITStructureRule]" definitions appropriate
+for the two (2) and three (3) dimensional models, keyed by the numeric OID
+of each model.
+
+Rules which bear no superior rules govern entries placed immediately beneath
+a registration base, such as "ou=Registrations,o=rA".
+
+[dITStructureRule]: https://www.rfc-editor.org/rfc/rfc4512.html#section-4.1.7.1
+*/
+var DITStructureRules map[string][]DITStructureRule = map[string][]DITStructureRule{
+	TwoDimensional: {
+		{ID: 3, Name: `dotNotationArcStructure`, Form: `dotNotationForm`},
+	},
+	ThreeDimensional: {
+		{ID: 1, Name: `rootArcStructure`, Form: `nRootArcForm`},
+		{ID: 2, Name: `arcStructure`, Form: `nArcForm`, Superior: []int{1, 2}},
+	},
+}
+
+/*
+IsZero returns a Boolean value indicative of a nil receiver state.
+*/
+func (r DITStructureRule) IsZero() bool {
+	return r.ID == 0
+}
+
+/*
+String returns the [RFC 4512 § 4.1.7.1] string representation of the
+receiver instance.
+
+[RFC 4512 § 4.1.7.1]: https://www.rfc-editor.org/rfc/rfc4512.html#section-4.1.7.1
+*/
+func (r DITStructureRule) String() (s string) {
+	if !r.IsZero() {
+		s = sprintf("( %d NAME '%s' FORM %s", r.ID, r.Name, r.Form)
+		switch len(r.Superior) {
+		case 0:
+		case 1:
+			s += sprintf(" SUP %d", r.Superior[0])
+		default:
+			var sup []string
+			for _, id := range r.Superior {
+				sup = append(sup, itoa(id))
+			}
+			s += ` SUP ( ` + join(sup, ` `) + ` )`
+		}
+		s += ` )`
+	}
+
+	return
+}
+
+/*
+permits returns a Boolean value indicative of whether the receiver allows
+placement beneath an entry governed by the sup rule identifier. A value of
+zero (0) indicates the superior is a registration base.
+*/
+func (r DITStructureRule) permits(sup int) (ok bool) {
+	if ok = sup == 0 && len(r.Superior) == 0; !ok {
+		for i := 0; i < len(r.Superior) && !ok; i++ {
+			ok = r.Superior[i] == sup
+		}
+	}
+
+	return
+}
+
+/*
+StructureRules returns the slices of [DITStructureRule] appropriate for
+the model in use by the receiver instance.
+*/
+func (r *DITProfile) StructureRules() (rules []DITStructureRule) {
+	if !r.IsZero() {
+		rules = DITStructureRules[r.Model()]
+	}
+
+	return
+}
+
+/*
+StructureRule returns the [DITStructureRule] which governs an entry bearing
+the input DN and structural object class, alongside an error following an
+attempt to validate the placement of the entry according to the model in
+use by the receiver instance.
+
+Each RDN between the entry and the registration base is examined, such that
+every superior is also expected to conform to the relevant structure rules.
+A zero structural object class will not be considered during this process.
+
+A [NameFormErr] is returned if any RDN does not conform to a name form
+permitted for the model or structural class, while a [StructureRuleErr] is
+returned if the RDN conforms to such a name form, but is not permitted to
+be placed beneath its superior.
+*/
+func (r *DITProfile) StructureRule(dn, soc string) (rule DITStructureRule, err error) {
+	rules := r.StructureRules()
+	if len(rules) == 0 {
+		err = InvalidDimensionErr
+		return
+	}
+
+	rdns := rdnSequence(dn)
+
+	var rel []string
+	for i := 0; i < r.NumRegistrationBase(); i++ {
+		base := rdnSequence(r.registrationBase(i))
+		if rdnSuffixEqual(rdns, base) && len(rdns)-len(base) > 0 &&
+			(rel == nil || len(rdns)-len(base) < len(rel)) {
+			rel = rdns[:len(rdns)-len(base)]
+		}
+	}
+
+	if len(rel) == 0 {
+		err = InvalidDNErr
+		return
+	}
+
+	// Begin with the uppermost RDN, which resides
+	// immediately beneath the registration base.
+	var sup int
+	for i := len(rel) - 1; i >= 0 && err == nil; i-- {
+		var class string
+		if i == 0 {
+			class = soc
+		}
+		if rule, err = structureRuleOf(rules, rel[i], class, sup); err == nil {
+			sup = rule.ID
+		}
+	}
+
+	if err != nil {
+		rule = DITStructureRule{}
+	}
+
+	return
+}
+
+func structureRuleOf(rules []DITStructureRule, rdn, soc string, sup int) (rule DITStructureRule, err error) {
+	at, val, err := rdnAttributeValue(rdn)
+	if err != nil {
+		return
+	}
+
+	err = wrapErr(NameFormErr, rdn)
+	for _, r := range rules {
+		form := nameForms[r.Form]
+		if !eq(form.must, at) || (soc != `` && !strInSlice(soc, form.oc)) {
+			continue
+		} else if (eq(at, `n`) && !isNumber(val)) ||
+			(eq(at, `dotNotation`) && !isNumber(val) && !IsNumericOID(val)) {
+			break
+		}
+
+		err = wrapErr(StructureRuleErr, rdn)
+		if r.permits(sup) {
+			rule = r
+			err = nil
+			break
+		}
+	}
+
+	return
+}
+
+/*
+rdnAttributeValue returns the attribute type and value of the input single
+valued RDN. An error is returned if the RDN is malformed or multi-valued, as
+no name form defined by the RASCHEMA I-D allows for the latter.
+*/
+func rdnAttributeValue(rdn string) (at, val string, err error) {
+	if len(splitUnescaped(rdn, `+`, `\`)) > 1 {
+		err = wrapErr(NameFormErr, "multi-valued RDN "+rdn)
+	} else if idx := idxr(rdn, '='); idx <= 0 || idx == len(rdn)-1 {
+		err = InvalidDNErr
+	} else {
+		at, val = trimS(rdn[:idx]), trimS(rdn[idx+1:])
+	}
+
+	return
+}
+
+/*
+StructureRule returns the [DITStructureRule] which governs the receiver
+instance, alongside an error following an attempt to validate its placement
+and naming. See [DITProfile.StructureRule] for details.
+
+In addition, the RDN value of the receiver must agree with the "[n]" or
+"[dotNotation]" value present, and any "[governingStructureRule]" value
+present must agree with the identifier of the rule returned.
+
+[n]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.1
+[dotNotation]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.2
+[governingStructureRule]: https://www.rfc-editor.org/rfc/rfc4512.html#section-3.4.6
+*/
+func (r *Registration) StructureRule() (rule DITStructureRule, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	}
+
+	if rule, err = r.Profile().StructureRule(r.DN(), r.StructuralObjectClass()); err != nil {
+		return
+	}
+
+	at, val, _ := rdnAttributeValue(rdnSequence(r.DN())[0])
+	if x := r.R_X680; !x.IsZero() {
+		if lit := x.R_N; eq(at, `n`) && lit != `` && lit != val {
+			err = wrapErr(NameFormErr, "RDN value differs from n "+lit)
+		} else if lit = x.R_DotNot; eq(at, `dotNotation`) && lit != `` && lit != val {
+			err = wrapErr(NameFormErr, "RDN value differs from dotNotation "+lit)
+		}
+	}
+
+	if gsr := r.GoverningStructureRule(); err == nil && gsr != `` && gsr != itoa(rule.ID) {
+		err = wrapErr(StructureRuleErr, "governingStructureRule "+
+			gsr+" should be "+itoa(rule.ID))
+	}
+
+	if err != nil {
+		rule = DITStructureRule{}
+	}
+
+	return
+}
@@ -178,10 +178,20 @@ func (r *Subentry) SubtreeSpecification() (sts []string) {
 SetSubtreeSpecification appends the provided string value to the receiver
 instance as a Subtree Specification. If an instance of []string is provided,
 the receiver value is clobbered (overwritten).
+
+Instances of [SubtreeSpecification] and *[SubtreeSpecificationBuilder] are
+also accepted, and are written in their canonical string form.
 */
 func (r *Subentry) SetSubtreeSpecification(args ...any) error {
 	if len(args) > 0 {
-		if ss, assert := args[0].(SubtreeSpecification); assert {
+		switch tv := args[0].(type) {
+		case SubtreeSpecification:
+			args[0] = tv.String()
+		case *SubtreeSpecificationBuilder:
+			ss, err := tv.Build()
+			if err != nil {
+				return err
+			}
 			args[0] = ss.String()
 		}
 	}
@@ -197,6 +207,49 @@ func (r *Subentry) SubtreeSpecificationGetFunc(getfunc GetOrSetFunc) (any, error
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `subtreeSpecification`)
 }
 
+/*
+Governs returns a Boolean value indicative of whether the input value
+falls within any of the "[subtreeSpecification]" values assigned to the
+receiver instance, alongside an error.
+
+The administrative point is derived from the DN of the receiver, as per
+[Section 2.4 of RFC 3672], subentries are immediate subordinates of their
+administrative point. A receiver which bears no subtree specifications
+is treated as though the default "{}" specification was assigned.
+
+See [SubtreeSpecification.Includes] for details regarding the input value x.
+
+[subtreeSpecification]: https://datatracker.ietf.org/doc/html/rfc3672#section-2.3
+[Section 2.4 of RFC 3672]: https://datatracker.ietf.org/doc/html/rfc3672#section-2.4
+*/
+func (r *Subentry) Governs(x any) (governs bool, err error) {
+	if r.IsZero() {
+		err = NilInstanceErr
+		return
+	}
+
+	dn := r.DN()
+	admin := superiorDN(dn)
+	if !r.validName(dn) || len(admin) == 0 {
+		err = InvalidDNErr
+		return
+	}
+
+	specs := r.SubtreeSpecification()
+	if len(specs) == 0 {
+		specs = []string{`{}`}
+	}
+
+	for i := 0; i < len(specs) && !governs && err == nil; i++ {
+		var ss SubtreeSpecification
+		if ss, err = NewSubtreeSpecification(specs[i]); err == nil {
+			governs, err = ss.Includes(admin, x)
+		}
+	}
+
+	return
+}
+
 /*
 CN returns the common name value assigned to the receiver instance.
 */
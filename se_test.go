@@ -2,7 +2,9 @@ package radir
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSubentry_codecov(t *testing.T) {
@@ -124,3 +126,347 @@ func TestSubentry_codecov(t *testing.T) {
 	subentries.IsZero()
 
 }
+
+/*
+This example demonstrates the creation of a "spatialContext" *[Subentry]
+which describes the sibling pool formed by the children of a *[Registration]
+through collective values.
+*/
+func ExampleRegistration_NewSpatialSubentry() {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.X680().SetASN1Notation(`{iso(1)}`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	org.NewChild(`6`, `dod`)
+	org.NewChild(`0`, `zero`)
+	org.NewChild(`27`, `example`)
+
+	sub, err := org.NewSpatialSubentry(`spatialContext`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(sub.LDIF())
+	// Output: dn: cn=spatialContext,n=3,n=1,ou=Registrations,o=rA
+	// objectClass: top
+	// objectClass: extensibleObject
+	// objectClass: subentry
+	// objectClass: collectiveAttributeSubentry
+	// objectClass: spatialContext
+	// cn: spatialContext
+	// subtreeSpecification: {minimum 1, maximum 1}
+	// c-supArc;collective: n=3,n=1,ou=Registrations,o=rA
+	// c-topArc;collective: n=1,ou=Registrations,o=rA
+	// c-minArc;collective: n=0,n=3,n=1,ou=Registrations,o=rA
+	// c-maxArc;collective: n=27,n=3,n=1,ou=Registrations,o=rA
+}
+
+func TestRegistration_NewSpatialSubentry(t *testing.T) {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	if _, err := org.NewSpatialSubentry(`spatialContext`); err != NoSubordinatesErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NoSubordinatesErr, err)
+		return
+	}
+
+	org.NewChild(`6`, `dod`)
+	sub, err := org.NewSpatialSubentry(`spatialContext`, `ou=Registrations,o=rA`)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	want := `{base "n=3,n=1", minimum 1, maximum 1}`
+	if got := sub.SubtreeSpecification(); len(got) != 1 || got[0] != want {
+		t.Errorf("%s failed: want '%s', got '%v'", t.Name(), want, got)
+		return
+	} else if sub.DN() != `cn=spatialContext,ou=Registrations,o=rA` {
+		t.Errorf("%s failed: unexpected DN '%s'", t.Name(), sub.DN())
+		return
+	} else if org.Subentries().Len() != 0 {
+		t.Errorf("%s failed: foreign subentry added to registration", t.Name())
+		return
+	}
+
+	for _, adm := range []string{`ou=Elsewhere,o=rA`, `Registrations,o=rA`} {
+		if _, err = org.NewSpatialSubentry(`spatialContext`, adm); err != InvalidDNErr {
+			t.Errorf("%s failed: want '%v', got '%v'", t.Name(), InvalidDNErr, err)
+			return
+		}
+	}
+
+	var nilReg *Registration
+	if _, err = nilReg.NewSpatialSubentry(`x`); err != NilRegistrationErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NilRegistrationErr, err)
+		return
+	}
+}
+
+func TestRegistration_Effective(t *testing.T) {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	dod := org.NewChild(`6`, `dod`)
+	org.NewChild(`9`, `nine`)
+
+	// Subentry beneath iso, governing its whole subtree
+	wide := iso.NewSubentry(`wide`)
+	wide.SetCTTL(`3600`)
+	wide.X660().SetCFirstAuthorities(`registrantID=A,ou=Registrants,o=rA`)
+
+	// Subentry beneath org, governing only its children
+	pool, err := org.NewSpatialSubentry(`spatialContext`)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+	pool.SetCTTL(`60`)
+	pool.X660().SetCFirstAuthorities(`registrantID=B,ou=Registrants,o=rA`)
+	pool.Supplement().SetCDiscloseTo(`cn=Everyone`)
+
+	eff, err := dod.Effective()
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	if got := eff.CTTL(); got != `60` {
+		t.Errorf("%s failed: want nearest c-rATTL '60', got '%s'", t.Name(), got)
+		return
+	} else if got := eff.X660().CFirstAuthorities(); len(got) != 2 {
+		t.Errorf("%s failed: want 2 merged c-firstAuthority values, got %v", t.Name(), got)
+		return
+	} else if got := eff.Spatial().CMinArc(); got != dod.DN() {
+		t.Errorf("%s failed: want c-minArc '%s', got '%s'", t.Name(), dod.DN(), got)
+		return
+	} else if got := eff.CollectiveAttributeSubentries(); len(got) != 2 {
+		t.Errorf("%s failed: want 2 collectiveAttributeSubentries, got %v", t.Name(), got)
+		return
+	} else if dod.CTTL() != `` || dod.R_Spatial != nil {
+		t.Errorf("%s failed: receiver was modified", t.Name())
+		return
+	}
+
+	// org is only governed by the wide subentry
+	if eff, _ = org.Effective(); eff.CTTL() != `3600` || eff.Spatial().CSupArc() != `` {
+		t.Errorf("%s failed: unexpected effective values for %s", t.Name(), org.DN())
+		return
+	}
+
+	// changes to the effective instance must not reach the receiver
+	eff.X680().R_Id = `changed`
+	eff.R_OC[0] = `changed`
+	if org.X680().Identifier() != `identified-organization` || org.R_OC[0] == `changed` {
+		t.Errorf("%s failed: effective instance aliases the receiver", t.Name())
+		return
+	}
+
+	dod.SetCollectiveExclusions(`c-rATTL`)
+	dod.SetCollectiveExclusions(RegistrationAttributeTypes[`c-discloseTo`])
+	if eff, _ = dod.Effective(); eff.CTTL() != `` || len(eff.Supplement().CDiscloseTo()) != 0 {
+		t.Errorf("%s failed: collectiveExclusions not honored", t.Name())
+		return
+	} else if len(eff.X660().CFirstAuthorities()) != 2 {
+		t.Errorf("%s failed: non-excluded values missing", t.Name())
+		return
+	}
+
+	dod.SetCollectiveExclusions([]string{`excludeAllCollectiveAttributes`})
+	if eff, _ = dod.Effective(); len(eff.X660().CFirstAuthorities()) != 0 {
+		t.Errorf("%s failed: excludeAllCollectiveAttributes not honored", t.Name())
+		return
+	}
+
+	var nilReg *Registration
+	if _, err = nilReg.Effective(); err != NilRegistrationErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NilRegistrationErr, err)
+		return
+	}
+
+	bogus := &Subentry{R_DN: `cn=bogus`}
+	if _, err = org.Effective(bogus); err == nil {
+		t.Errorf("%s failed: expected error for malformed subentry DN", t.Name())
+		return
+	}
+}
+
+func TestRegistration_ResolveTTL(t *testing.T) {
+	prof := &DITProfile{R_Settings: newProfileSettings()}
+	prof.SetModel(ThreeDimensional)
+	prof.SetRegistrationBase(`ou=Registrations,o=rA`)
+	prof.SetRegistrantBase(`ou=Registrants,o=rA`)
+	prof.R_DN = `cn=profile,o=rA`
+	prof.R_TTL = `86400`
+
+	iso := prof.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	dod := org.NewChild(`6`, `dod`)
+
+	for _, tc := range []struct {
+		reg  *Registration
+		dur  time.Duration
+		src  TTLSource
+		dn   string
+		prep func()
+	}{
+		{dod, 86400 * time.Second, ProfileTTL, prof.R_DN, func() {}},
+		// rATTL is not collective, and does not apply to descendants
+		{dod, 86400 * time.Second, ProfileTTL, prof.R_DN, func() { iso.SetTTL(`3600`) }},
+		{dod, time.Minute, CollectiveTTL, `cn=pool,` + iso.DN(), func() {
+			iso.NewSubentry(`pool`).SetCTTL(`60`)
+		}},
+		{dod, 86400 * time.Second, ProfileTTL, prof.R_DN, func() { dod.SetCollectiveExclusions(`c-rATTL`) }},
+		{dod, 0, LiteralTTL, dod.DN(), func() { dod.SetTTL(`0`) }},
+	} {
+		tc.prep()
+		ttl, err := tc.reg.ResolveTTL()
+		if err != nil {
+			t.Errorf("%s failed: %v", t.Name(), err)
+			return
+		} else if ttl.Duration != tc.dur || ttl.Source != tc.src || ttl.DN != tc.dn {
+			t.Errorf("%s failed: want %s (%s via %s), got %s",
+				t.Name(), tc.dur, tc.src, tc.dn, ttl)
+			return
+		}
+	}
+
+	if ttl, _ := dod.ResolveTTL(); ttl.Cacheable() || ttl.CacheControl() != `no-store` {
+		t.Errorf("%s failed: zero TTL must not be cacheable", t.Name())
+		return
+	} else if ttl, _ = iso.ResolveTTL(); ttl.CacheControl() != `max-age=3600` {
+		t.Errorf("%s failed: unexpected Cache-Control '%s'", t.Name(), ttl.CacheControl())
+		return
+	}
+
+	org.SetTTL(`soon`)
+	if _, err := org.ResolveTTL(); err == nil {
+		t.Errorf("%s failed: expected error for malformed TTL", t.Name())
+		return
+	}
+
+	var nilReg *Registration
+	if _, err := nilReg.ResolveTTL(); err != NilRegistrationErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NilRegistrationErr, err)
+		return
+	}
+}
+
+func TestRegistration_PlanCollectives(t *testing.T) {
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,ou=Registrations,o=rA`)
+	iso.X680().SetN(`1`)
+	iso.SetTTL(`60`)
+
+	org := iso.NewChild(`3`, `identified-organization`)
+	org.SetTTL(`3600`)
+	for _, nf := range []string{`6`, `9`, `40`} {
+		kid := org.NewChild(nf, ``)
+		kid.SetTTL(`3600`)
+		kid.X660().SetFirstAuthorities(`registrantID=A,ou=Registrants,o=rA`)
+	}
+
+	iso.CheckSpatial(true)
+
+	plan, err := iso.PlanCollectives(`collective`)
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	want := map[string]string{
+		`cn=collective-1,n=1,ou=Registrations,o=rA`: `{minimum 1}`,
+		`cn=collective-2,n=1,ou=Registrations,o=rA`: `{base "n=3", minimum 1}`,
+		`cn=collective-3,n=1,ou=Registrations,o=rA`: `{}`,
+	}
+
+	if plan.Subentries.Len() != len(want) {
+		t.Errorf("%s failed: want %d subentries, got %d", t.Name(), len(want), plan.Subentries.Len())
+		return
+	}
+
+	for i := 0; i < plan.Subentries.Len(); i++ {
+		se := plan.Subentries.Index(i)
+		if sts := se.SubtreeSpecification(); len(sts) != 1 || sts[0] != want[se.DN()] {
+			t.Errorf("%s failed: unexpected subtreeSpecification for %s: %v", t.Name(), se.DN(), sts)
+			return
+		}
+	}
+
+	pool := plan.Subentries.Index(1)
+	if pool.Spatial().CSupArc() != org.DN() || len(pool.X660().CFirstAuthorities()) != 1 {
+		t.Errorf("%s failed: pool subentry lacks collective values", t.Name())
+		return
+	} else if plan.Subentries.Index(0).CTTL() != `3600` {
+		t.Errorf("%s failed: want c-rATTL 3600, got '%s'", t.Name(), plan.Subentries.Index(0).CTTL())
+		return
+	}
+
+	// Apply the deletions, then verify materialization
+	// restores the very same literal values.
+	before := make(map[string][]string)
+	for _, del := range plan.Deletions {
+		reg := iso.treeRoot().findDN(del.DN)
+		inst := []any{reg, reg.R_X660, reg.R_Spatial, reg.R_Extra}
+		for _, x := range inst {
+			if f, err := getFieldByNameTag(x, del.Type); err == nil {
+				before[del.DN+del.Type] = readFieldByTag(del.Type, x)
+				f.Set(reflect.Zero(f.Type()))
+				break
+			}
+		}
+	}
+
+	if len(before) != len(plan.Deletions) {
+		t.Errorf("%s failed: could not apply all %d deletions", t.Name(), len(plan.Deletions))
+		return
+	}
+
+	for i := 0; i < plan.Subentries.Len(); i++ {
+		iso.Subentries().Push(plan.Subentries.Index(i))
+	}
+
+	adds, err := iso.MaterializeCollectives()
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if len(adds) != len(plan.Deletions) {
+		t.Errorf("%s failed: want %d additions, got %d", t.Name(), len(plan.Deletions), len(adds))
+		return
+	}
+
+	for _, add := range adds {
+		if got := before[add.DN+add.Type]; len(got) != len(add.Values) || got[0] != add.Values[0] {
+			t.Errorf("%s failed: materialized %s for %s: want %v, got %v",
+				t.Name(), add.Type, add.DN, got, add.Values)
+			return
+		}
+	}
+
+	if iso.TTL() != `60` || org.TTL() != `3600` {
+		t.Errorf("%s failed: literal TTLs not preserved or restored", t.Name())
+		return
+	}
+
+	var nilReg *Registration
+	if _, err = nilReg.PlanCollectives(`x`); err != NilRegistrationErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NilRegistrationErr, err)
+		return
+	} else if _, err = nilReg.MaterializeCollectives(); err != NilRegistrationErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NilRegistrationErr, err)
+		return
+	} else if _, err = iso.PlanCollectives(``); err != InvalidDNErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), InvalidDNErr, err)
+		return
+	}
+}
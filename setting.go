@@ -0,0 +1,602 @@
+package radir
+
+/*
+setting.go contains typed setting definitions, and the means of loading
+ProfileSettings from JSON, environment variables and LDAP.
+*/
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+SettingType describes the type of value borne by a setting declared
+through [DefineSetting].
+*/
+type SettingType uint8
+
+const (
+	_               SettingType = iota
+	IntSetting                  // int
+	DurationSetting             // time.Duration
+	BoolSetting                 // bool
+	StringSetting               // string
+	ListSetting                 // []string
+)
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r SettingType) String() (s string) {
+	switch r {
+	case IntSetting:
+		s = `int`
+	case DurationSetting:
+		s = `duration`
+	case BoolSetting:
+		s = `bool`
+	case StringSetting:
+		s = `string`
+	case ListSetting:
+		s = `list`
+	}
+
+	return
+}
+
+/*
+SettingDefinition describes a single setting which may be borne by any
+instance of [ProfileSettings], and is declared through [DefineSetting].
+*/
+type SettingDefinition struct {
+	Name    string          // case-significant key name
+	Type    SettingType     // type of value
+	Default any             // returned if the setting is unset; may be nil
+	Desc    string          // optional description
+	Check   func(any) error // optional check of a correctly-typed value
+}
+
+/*
+settingDefs contains all declared setting definitions, keyed by name, and
+is guarded by settingMutex.
+*/
+var (
+	settingDefs  map[string]SettingDefinition = make(map[string]SettingDefinition)
+	settingMutex sync.RWMutex
+)
+
+/*
+settingType is the attribute type by which settings are conveyed. It is not
+defined by the I-D series, and requires a suitable definition within the RA
+DSA schema.
+*/
+const settingType = `rADUASetting`
+
+/*
+settingCarrier receives the "rADUASetting" values of an entry during
+[DITProfile.Marshal].
+*/
+type settingCarrier struct {
+	R_Vals []string `ldap:"rADUASetting"`
+}
+
+/*
+DefineSetting declares the input [SettingDefinition] instances, each of
+which replaces any prior definition of the same name. Definitions apply
+to all instances of [ProfileSettings], and may be declared concurrently,
+though should ideally be declared during initialization.
+
+Once declared, a setting which is unset within an instance of [ProfileSettings]
+yields its default value, while the values of a set setting are subject to
+[ProfileSettings.Validate]. Declared settings may also be loaded through
+[ProfileSettings.LoadJSON], [ProfileSettings.LoadEnv] and [DITProfile.Marshal],
+and are conveyed by [DITProfile.LDIF] and [DITProfile.Unmarshal] if enabled
+through [DITProfile.UseSettingValues].
+
+The default value may be input in any form accepted by [ProfileSettings.LoadJSON],
+such as "30s" for a [DurationSetting].
+
+An error is returned -- and no definitions are declared -- if any definition
+bears no name, bears an unknown [SettingType], or bears a default value that
+cannot be converted to its type or fails its check.
+*/
+func DefineSetting(defs ...SettingDefinition) (err error) {
+	for i := 0; i < len(defs); i++ {
+		def := &defs[i]
+		if len(def.Name) == 0 || ctns(def.Name, `=`) || len(def.Type.String()) == 0 {
+			err = wrapErr(InvalidSettingErr, "bad definition '"+def.Name+"'")
+		} else if def.Default != nil {
+			if def.Default, err = def.coerce(def.Default); err == nil {
+				err = def.check(def.Default)
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	settingMutex.Lock()
+	for _, def := range defs {
+		settingDefs[def.Name] = def
+	}
+	settingMutex.Unlock()
+
+	return
+}
+
+/*
+UseSettingValues declares whether the values of settings declared through
+[DefineSetting] shall be conveyed as "name=value" values of the "rADUASetting"
+attribute type by [DITProfile.LDIF] and [DITProfile.Unmarshal].
+
+This attribute type is not defined by the I-D series, and requires a suitable
+definition within the RA DSA schema. This feature is disabled by default. Note
+that [DITProfile.Marshal] always loads such values, if present.
+*/
+func (r *DITProfile) UseSettingValues(use bool) {
+	if !r.IsZero() {
+		r.r_settings = use
+	}
+}
+
+/*
+settingValues returns the "rADUASetting" values of the receiver, or nil if
+not enabled. See [DITProfile.UseSettingValues].
+*/
+func (r *DITProfile) settingValues() (vals []string) {
+	if !r.IsZero() && r.r_settings {
+		vals = r.R_Settings.ldapValues()
+	}
+
+	return
+}
+
+/*
+SettingDefinitions returns slices of [SettingDefinition], each describing
+a setting declared through [DefineSetting], ordered by name.
+*/
+func SettingDefinitions() (defs []SettingDefinition) {
+	for _, name := range definedSettings() {
+		if def, ok := settingDef(name); ok {
+			defs = append(defs, def)
+		}
+	}
+
+	return
+}
+
+func definedSettings() (names []string) {
+	settingMutex.RLock()
+	for name := range settingDefs {
+		names = append(names, name)
+	}
+	settingMutex.RUnlock()
+	sortStrs(names)
+
+	return
+}
+
+func settingDef(name string) (def SettingDefinition, ok bool) {
+	settingMutex.RLock()
+	def, ok = settingDefs[name]
+	settingMutex.RUnlock()
+
+	return
+}
+
+/*
+coerce returns the input value converted to the type of the receiver,
+alongside an error. Strings are parsed, while JSON numbers and arrays are
+converted where lossless.
+*/
+func (r SettingDefinition) coerce(value any) (out any, err error) {
+	switch r.Type {
+	case IntSetting:
+		switch tv := value.(type) {
+		case int:
+			out = tv
+		case int64:
+			out = int(tv)
+		case float64:
+			if tv == float64(int(tv)) {
+				out = int(tv)
+			}
+		case string:
+			out, err = atoi(trimS(tv))
+		}
+	case DurationSetting:
+		switch tv := value.(type) {
+		case time.Duration:
+			out = tv
+		case float64:
+			out = time.Duration(tv)
+		case string:
+			out, err = time.ParseDuration(trimS(tv))
+		}
+	case BoolSetting:
+		switch tv := value.(type) {
+		case bool:
+			out = tv
+		case string:
+			out, err = strconv.ParseBool(trimS(tv))
+		}
+	case StringSetting:
+		if tv, ok := value.(string); ok {
+			out = tv
+		}
+	case ListSetting:
+		switch tv := value.(type) {
+		case []string:
+			out = append([]string{}, tv...)
+		case []any:
+			list := make([]string, 0, len(tv))
+			for _, v := range tv {
+				if s, ok := v.(string); ok {
+					list = append(list, s)
+				}
+			}
+			if len(list) == len(tv) {
+				out = list
+			}
+		case string:
+			var list []string
+			for _, v := range split(tv, `,`) {
+				if v = trimS(v); len(v) > 0 {
+					list = append(list, v)
+				}
+			}
+			out = list
+		}
+	}
+
+	if err != nil || out == nil {
+		out = nil
+		err = wrapErr(InvalidSettingErr, "'"+r.Name+"' requires "+r.Type.String())
+	}
+
+	return
+}
+
+/*
+check returns an error if the input value is not of the type of the
+receiver, or if it fails the check of the receiver.
+*/
+func (r SettingDefinition) check(value any) (err error) {
+	var ok bool
+	switch r.Type {
+	case IntSetting:
+		_, ok = value.(int)
+	case DurationSetting:
+		_, ok = value.(time.Duration)
+	case BoolSetting:
+		_, ok = value.(bool)
+	case StringSetting:
+		_, ok = value.(string)
+	case ListSetting:
+		_, ok = value.([]string)
+	}
+
+	if !ok {
+		err = wrapErr(InvalidSettingErr, "'"+r.Name+"' requires "+r.Type.String())
+	} else if r.Check != nil {
+		if err = r.Check(value); err != nil {
+			err = wrapErr(InvalidSettingErr, "'"+r.Name+"': "+err.Error())
+		}
+	}
+
+	return
+}
+
+/*
+format returns the string values of the input value, which must be of the
+type of the receiver.
+*/
+func (r SettingDefinition) format(value any) (vals []string) {
+	switch tv := value.(type) {
+	case int:
+		vals = []string{itoa(tv)}
+	case time.Duration:
+		vals = []string{tv.String()}
+	case bool:
+		vals = []string{strconv.FormatBool(tv)}
+	case string:
+		vals = []string{tv}
+	case []string:
+		vals = tv
+	}
+
+	return
+}
+
+/*
+IntValue returns an asserted int value associated with key alongside a
+Boolean value indicative of a successful key match and value assertion.
+The default value of a declared setting is returned if the key is unset.
+
+Case is significant in the matching process.
+*/
+func (r *ProfileSettings) IntValue(key string) (value int, ok bool) {
+	if avalue, aok := r.Value(key); aok {
+		value, ok = avalue.(int)
+	}
+
+	return
+}
+
+/*
+DurationValue returns an asserted [time.Duration] value associated with key
+alongside a Boolean value indicative of a successful key match and value
+assertion. The default value of a declared setting is returned if the key
+is unset.
+
+Case is significant in the matching process.
+*/
+func (r *ProfileSettings) DurationValue(key string) (value time.Duration, ok bool) {
+	if avalue, aok := r.Value(key); aok {
+		value, ok = avalue.(time.Duration)
+	}
+
+	return
+}
+
+/*
+Validate returns an error if any value of a declared setting present within
+the receiver is not of the declared [SettingType], or fails the check of its
+[SettingDefinition]. Undeclared settings are not validated.
+*/
+func (r *ProfileSettings) Validate() (err error) {
+	if r.IsZero() {
+		err = NilInstanceErr
+		return
+	}
+
+	for _, name := range definedSettings() {
+		def, _ := settingDef(name)
+		if value, found := (*r)[name]; found {
+			if err = def.check(value); err != nil {
+				break
+			}
+		}
+	}
+
+	return
+}
+
+/*
+Parse assigns the input string values to key, returning an error if the
+values cannot be converted to the [SettingType] of a declared setting, or
+fail its check. A sole value is required for all types other than [ListSetting],
+whose values are assigned as-is.
+
+The sole value of an undeclared setting is assigned as a string, while
+multiple values are assigned as slices of string.
+*/
+func (r *ProfileSettings) Parse(key string, values ...string) (err error) {
+	if r.IsZero() {
+		err = NilInstanceErr
+		return
+	}
+
+	var value any
+	if value, err = parseSetting(key, values...); err == nil {
+		(*r)[key] = value
+	}
+
+	return
+}
+
+func parseSetting(key string, values ...string) (value any, err error) {
+	def, defined := settingDef(key)
+	switch {
+	case len(key) == 0 || len(values) == 0:
+		err = wrapErr(InvalidSettingErr, "no key or values")
+	case !defined:
+		if value = values[0]; len(values) > 1 {
+			value = append([]string{}, values...)
+		}
+	case def.Type == ListSetting:
+		value = append([]string{}, values...)
+		err = def.check(value)
+	case len(values) > 1:
+		err = wrapErr(InvalidSettingErr, "'"+key+"' is single-valued")
+	default:
+		if value, err = def.coerce(values[0]); err == nil {
+			err = def.check(value)
+		}
+	}
+
+	return
+}
+
+/*
+LoadJSON reads the JSON file at the input path, and assigns each member of
+its top-level object to the receiver. See [ProfileSettings.UnmarshalJSON] for
+details.
+*/
+func (r *ProfileSettings) LoadJSON(path string) (err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err == nil {
+		err = r.UnmarshalJSON(data)
+	}
+
+	return
+}
+
+/*
+UnmarshalJSON assigns each member of the input JSON object to the receiver,
+thereby satisfying the [encoding/json.Unmarshaler] interface.
+
+Values of declared settings are converted to the [SettingType] of the setting.
+An integer is expected of an [IntSetting], while a [DurationSetting] may bear
+a string (e.g.: "1m30s") or a number of nanoseconds. A [BoolSetting] may bear
+a Boolean or its string form, while a [ListSetting] may bear an array of strings
+or a single comma-delimited string. Values of undeclared settings are assigned
+as decoded, except for arrays of strings, which are assigned as slices of string.
+
+No values are assigned if any value cannot be converted, or fails its check.
+*/
+func (r *ProfileSettings) UnmarshalJSON(data []byte) (err error) {
+	if r.IsZero() {
+		err = NilInstanceErr
+		return
+	}
+
+	var raw map[string]any
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+
+	loaded := make(ProfileSettings)
+	for key, value := range raw {
+		if def, defined := settingDef(key); defined {
+			if value, err = def.coerce(value); err == nil {
+				err = def.check(value)
+			}
+		} else if arr, isArr := value.([]any); isArr {
+			if list, lerr := (SettingDefinition{Type: ListSetting}).coerce(arr); lerr == nil {
+				value = list
+			}
+		}
+
+		if err != nil {
+			return
+		}
+		loaded[key] = value
+	}
+
+	r.absorb(loaded)
+
+	return
+}
+
+/*
+LoadEnv assigns the values of environment variables which correspond to
+declared settings to the receiver.
+
+The name of each variable is the input prefix, verbatim, followed by the
+setting name in upper case, wherein each lower case letter or digit which
+precedes an upper case letter is followed by an underscore, and all other
+characters are replaced by underscores. For example, given a prefix of
+"RADUA_", the "cacheSize" setting corresponds to the "RADUA_CACHE_SIZE"
+variable.
+
+Values of a [ListSetting] are comma-delimited. No values are assigned if
+any value cannot be converted, or fails its check.
+*/
+func (r *ProfileSettings) LoadEnv(prefix string) (err error) {
+	if r.IsZero() {
+		err = NilInstanceErr
+		return
+	}
+
+	loaded := make(ProfileSettings)
+	for _, name := range definedSettings() {
+		env, found := os.LookupEnv(prefix + settingEnvName(name))
+		if !found {
+			continue
+		}
+
+		vals := []string{env}
+		if def, _ := settingDef(name); def.Type == ListSetting {
+			vals = nil
+			for _, v := range split(env, `,`) {
+				if v = trimS(v); len(v) > 0 {
+					vals = append(vals, v)
+				}
+			}
+		}
+
+		if loaded[name], err = parseSetting(name, vals...); err != nil {
+			return
+		}
+	}
+
+	r.absorb(loaded)
+
+	return
+}
+
+func settingEnvName(name string) string {
+	bld := newBuilder()
+	var prev rune
+	for _, ch := range name {
+		switch {
+		case isUpper(ch) && (isLower(prev) || isDigit(prev)):
+			bld.WriteRune('_')
+			bld.WriteRune(ch)
+		case isLetter(ch) || isDigit(ch):
+			bld.WriteString(uc(string(ch)))
+		default:
+			bld.WriteRune('_')
+		}
+		prev = ch
+	}
+
+	return bld.String()
+}
+
+/*
+ldapValues returns the "name=value" string values of each declared setting
+present within the receiver whose value is valid, ordered by name.
+*/
+func (r *ProfileSettings) ldapValues() (vals []string) {
+	if r.IsZero() {
+		return
+	}
+
+	for _, name := range definedSettings() {
+		def, _ := settingDef(name)
+		if value, found := (*r)[name]; found && def.check(value) == nil {
+			for _, v := range def.format(value) {
+				vals = append(vals, name+`=`+v)
+			}
+		}
+	}
+
+	return
+}
+
+/*
+parseLDAP assigns the input "name=value" string values to the receiver.
+Multiple values of the same name are assigned as a whole. No values are
+assigned if any value is malformed, cannot be converted, or fails its check.
+*/
+func (r *ProfileSettings) parseLDAP(vals []string) (err error) {
+	var names []string
+	grouped := make(map[string][]string)
+	for _, val := range vals {
+		idx := idxr(val, '=')
+		if idx < 1 {
+			err = wrapErr(InvalidSettingErr, "expected name=value, got '"+val+"'")
+			return
+		}
+
+		name := val[:idx]
+		if _, found := grouped[name]; !found {
+			names = append(names, name)
+		}
+		grouped[name] = append(grouped[name], val[idx+1:])
+	}
+
+	loaded := make(ProfileSettings)
+	for _, name := range names {
+		if loaded[name], err = parseSetting(name, grouped[name]...); err != nil {
+			return
+		}
+	}
+
+	r.absorb(loaded)
+
+	return
+}
+
+func (r *ProfileSettings) absorb(loaded ProfileSettings) {
+	for key, value := range loaded {
+		(*r)[key] = value
+	}
+}
@@ -22,7 +22,8 @@ type Spatial struct {
 	RC_MinArc string `ldap:"c-minArc;collective"` // RASCHEMA § 2.3.28
 	RC_MaxArc string `ldap:"c-maxArc;collective"` // RASCHEMA § 2.3.31
 
-	r_se bool
+	r_reg *Registration
+	r_se  bool
 }
 
 /*
@@ -34,11 +35,10 @@ func (r *Registration) Spatial() *Spatial {
 		return &Spatial{}
 	}
 
-	//
-
 	if r.R_Spatial.IsZero() {
 		r.R_Spatial = new(Spatial)
 	}
+	r.R_Spatial.r_reg = r
 
 	return r.R_Spatial
 }
@@ -379,3 +379,681 @@ an error.
 func (r *Spatial) SubArcGetFunc(getfunc GetOrSetFunc) (any, error) {
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `subArc`)
 }
+
+/*
+Up returns the *[Registration] referenced by the "[supArc]" value of the
+receiver, or by the "[c-supArc]" value if the former is unset, alongside
+an error.
+
+The target is first sought within the tree to which the receiver belongs.
+If not found, the optional [GetOrSetFunc] loader is executed with the DN
+and the receiver as its arguments. It must return a *[Registration] when
+successful.
+
+A zero instance is returned without error if no DN is set, or if the DN
+could not be resolved and no loader was provided.
+
+[supArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.21
+[c-supArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.22
+*/
+func (r *Registration) Up(loader ...GetOrSetFunc) (*Registration, error) {
+	return r.navigate(r.Spatial().SupArc(), r.Spatial().CSupArc(), loader...)
+}
+
+/*
+Top returns the *[Registration] referenced by the "[topArc]" value of the
+receiver, or by the "[c-topArc]" value if the former is unset, alongside
+an error.
+
+See [Registration.Up] for details regarding resolution and use of the
+optional [GetOrSetFunc] loader.
+
+[topArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.23
+[c-topArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.24
+*/
+func (r *Registration) Top(loader ...GetOrSetFunc) (*Registration, error) {
+	return r.navigate(r.Spatial().TopArc(), r.Spatial().CTopArc(), loader...)
+}
+
+/*
+Left returns the *[Registration] referenced by the "[leftArc]" value of
+the receiver alongside an error.
+
+See [Registration.Up] for details regarding resolution and use of the
+optional [GetOrSetFunc] loader.
+
+[leftArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.26
+*/
+func (r *Registration) Left(loader ...GetOrSetFunc) (*Registration, error) {
+	return r.navigate(r.Spatial().LeftArc(), ``, loader...)
+}
+
+/*
+Right returns the *[Registration] referenced by the "[rightArc]" value of
+the receiver alongside an error.
+
+See [Registration.Up] for details regarding resolution and use of the
+optional [GetOrSetFunc] loader.
+
+[rightArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.29
+*/
+func (r *Registration) Right(loader ...GetOrSetFunc) (*Registration, error) {
+	return r.navigate(r.Spatial().RightArc(), ``, loader...)
+}
+
+/*
+First returns the *[Registration] referenced by the "[minArc]" value of
+the receiver, or by the "[c-minArc]" value if the former is unset,
+alongside an error.
+
+See [Registration.Up] for details regarding resolution and use of the
+optional [GetOrSetFunc] loader.
+
+[minArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.27
+[c-minArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.28
+*/
+func (r *Registration) First(loader ...GetOrSetFunc) (*Registration, error) {
+	return r.navigate(r.Spatial().MinArc(), r.Spatial().CMinArc(), loader...)
+}
+
+/*
+Last returns the *[Registration] referenced by the "[maxArc]" value of
+the receiver, or by the "[c-maxArc]" value if the former is unset,
+alongside an error.
+
+See [Registration.Up] for details regarding resolution and use of the
+optional [GetOrSetFunc] loader.
+
+[maxArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.30
+[c-maxArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.31
+*/
+func (r *Registration) Last(loader ...GetOrSetFunc) (*Registration, error) {
+	return r.navigate(r.Spatial().MaxArc(), r.Spatial().CMaxArc(), loader...)
+}
+
+/*
+Down returns an instance of *[Registrations] containing each *[Registration]
+referenced by the "[subArc]" values of the receiver alongside an error.
+
+Values that were reduced to raw number forms, as described within the
+[Spatial.SubArc] documentation, are resolved against the receiver's own
+[Registration.Children].
+
+See [Registration.Up] for details regarding resolution and use of the
+optional [GetOrSetFunc] loader. Values which cannot be resolved within
+the loaded tree are skipped if no loader is provided. Otherwise, the first
+error returned by way of the loader ends the process and is returned.
+
+[subArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.25
+*/
+func (r *Registration) Down(loader ...GetOrSetFunc) (regs *Registrations, err error) {
+	subs := make(Registrations, 0)
+	regs = &subs
+
+	if r.IsZero() || r.R_Spatial.IsZero() {
+		return
+	}
+
+	for _, sub := range r.R_Spatial.SubArc() {
+		var reg *Registration
+		if isNumber(sub) {
+			reg = r.Children().Get(sub)
+		} else if reg, err = r.navigate(sub, ``, loader...); err != nil {
+			return
+		}
+
+		if !reg.IsZero() {
+			subs = append(subs, reg)
+		}
+	}
+
+	*regs = subs
+
+	return
+}
+
+/*
+navigate resolves the first non-zero DN among literal and collective to an
+instance of *[Registration], first by searching the loaded tree and then by
+way of the optional loader.
+*/
+func (r *Registration) navigate(literal, collective string, loader ...GetOrSetFunc) (reg *Registration, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	}
+
+	dn := literal
+	if len(dn) == 0 {
+		dn = collective
+	}
+
+	if len(dn) == 0 {
+		return
+	}
+
+	if reg = r.treeRoot().findDN(dn); !reg.IsZero() || len(loader) == 0 {
+		return
+	} else if loader[0] == nil {
+		err = NilGetOrSetFuncErr
+		return
+	}
+
+	var x any
+	if x, err = loader[0](dn, r); err == nil {
+		var ok bool
+		if reg, ok = x.(*Registration); !ok || reg.IsZero() {
+			reg = nil
+			err = NilRegistrationErr
+		}
+	}
+
+	return
+}
+
+/*
+treeRoot returns the uppermost *[Registration] ancestor of the receiver,
+as linked through [Registration.Parent]. If the receiver has no parent,
+the receiver itself is returned.
+*/
+func (r *Registration) treeRoot() (root *Registration) {
+	root = r
+	for !root.Parent().IsZero() {
+		root = root.Parent()
+	}
+
+	return
+}
+
+/*
+findDN returns the *[Registration] bearing the input DN within the progeny
+of the receiver, including the receiver itself. Case is not significant in
+the matching process.
+*/
+func (r *Registration) findDN(dn string) (reg *Registration) {
+	if r.IsZero() {
+		return
+	}
+
+	if eq(r.DN(), dn) {
+		reg = r
+		return
+	}
+
+	K := r.Children()
+	for i := 0; i < K.Len() && reg.IsZero(); i++ {
+		reg = K.Index(i).findDN(dn)
+	}
+
+	return
+}
+
+/*
+SpatialFaultKind describes the nature of a single *[Spatial] inconsistency
+reported by [Registration.CheckSpatial].
+*/
+type SpatialFaultKind uint8
+
+const (
+	_                 SpatialFaultKind = iota
+	WrongSpatialArc                    // value references the wrong registration
+	StaleSpatialArc                    // minArc or maxArc no longer reflects the sibling pool
+	NonRootTopArc                      // topArc does not reference the root arc
+	MissingSubArc                      // subArc values omit one or more children
+	DanglingSpatialDN                  // value references a registration not present in the tree
+)
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r SpatialFaultKind) String() (s string) {
+	switch r {
+	case WrongSpatialArc:
+		s = `wrong arc`
+	case StaleSpatialArc:
+		s = `stale arc`
+	case NonRootTopArc:
+		s = `non-root topArc`
+	case MissingSubArc:
+		s = `missing subArc`
+	case DanglingSpatialDN:
+		s = `dangling DN`
+	}
+
+	return
+}
+
+/*
+SpatialFault describes a single spatial attribute value found to disagree
+with the actual ordering of a *[Registration] tree.
+*/
+type SpatialFault struct {
+	DN     string           // DN of the offending registration
+	Type   string           // spatial attribute type, e.g.: "leftArc"
+	Value  string           // value found, or zero if absent
+	Expect string           // value expected, or zero if indeterminate
+	Kind   SpatialFaultKind // nature of the fault
+}
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r SpatialFault) String() string {
+	return sprintf("%s: %s %s (got '%s', want '%s')",
+		r.DN, r.Kind, r.Type, r.Value, r.Expect)
+}
+
+/*
+CheckSpatial returns zero or more instances of [SpatialFault], each of which
+describes a literal *[Spatial] value that disagrees with the actual ordering
+of the tree to which the receiver belongs. The entire tree, beginning with
+the uppermost ancestor of the receiver, is examined.
+
+Sibling order is determined according to number form magnitude, regardless
+of slice order. The following conditions are reported:
+
+  - A "[leftArc]" or "[rightArc]" value referencing a registration other than the nearest sibling
+  - A "[minArc]" or "[maxArc]" value not referencing the lowest or highest numbered sibling
+  - A "[supArc]" value not referencing the parent registration
+  - A "[topArc]" value not referencing the root arc of the receiver's numeric OID
+  - A "[subArc]" value set which omits one or more child registrations
+  - Any value (including collective variants) referencing a DN not present within the tree
+
+Unset values are not considered faulty, as use of the "[spatialContext]"
+class is optional. As the tree may represent a subtree of the DIT, the expected
+"[topArc]" value, and the "[supArc]" value of the uppermost registration, are
+derived from the numeric OID of the bearer rather than from the loaded tree. Raw number form "[subArc]" values are resolved against
+the children of the relevant registration. Note that "[leftArc]" and
+"[rightArc]" values of the lowest and highest numbered siblings are expected
+to reference the bearer, per [Registrations.SetXAxes].
+
+If the variadic repair Boolean is true, all literal X and Y axis values of
+the tree are cleared, each sibling pool is sorted using [Registrations.SortByNumberForm]
+and all values are then rewritten by way of [Registrations.SetXAxes] and
+[Registrations.SetYAxes]. The "[topArc]" value written is that expected by
+this method, or the DN of the root of the tree if it cannot be derived. The
+faults returned are those found prior to repair.
+
+[spatialContext]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5.11
+[supArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.21
+[topArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.23
+[subArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.25
+[leftArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.26
+[minArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.27
+[rightArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.29
+[maxArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.30
+*/
+func (r *Registration) CheckSpatial(repair ...bool) (faults []SpatialFault) {
+	if r.IsZero() {
+		return
+	}
+
+	root := r.treeRoot()
+	root.checkSpatial(root, &faults)
+
+	if len(repair) > 0 && repair[0] {
+		root.repairSpatial()
+	}
+
+	return
+}
+
+func (r *Registration) checkSpatial(root *Registration, faults *[]SpatialFault) {
+	if s := r.R_Spatial; !s.IsZero() {
+		fault := func(typ, val, want string, kind SpatialFaultKind) {
+			*faults = append(*faults, SpatialFault{
+				DN:     r.DN(),
+				Type:   typ,
+				Value:  val,
+				Expect: want,
+				Kind:   kind,
+			})
+		}
+
+		var want [6]string // sup, top, min, max, left, right
+		var known [6]bool
+		want[0], known[0] = r.Parent().DN(), true
+		if r.Parent().IsZero() && !r.IsRoot() {
+			// the parent was not loaded, thus derive
+			// its DN from our own dotNotation.
+			want[0], known[0] = r.spatialDN(-1)
+		}
+		if want[1], known[1] = r.spatialDN(1); !known[1] && root.IsRoot() {
+			want[1], known[1] = root.DN(), true
+		}
+		if sibs := r.sortedSiblings(); len(sibs) > 0 {
+			want[2], want[3] = sibs[0].DN(), sibs[len(sibs)-1].DN()
+			for i := 0; i < len(sibs); i++ {
+				if sibs[i] == r {
+					want[4], want[5] = want[2], want[3]
+					if i > 0 {
+						want[4] = sibs[i-1].DN()
+					}
+					if i < len(sibs)-1 {
+						want[5] = sibs[i+1].DN()
+					}
+				}
+			}
+			known[2], known[3], known[4], known[5] = true, true, true, true
+		}
+
+		// check each literal and collective DN for
+		// dangling references before anything else,
+		// excusing any expected value which resides
+		// outside of the loaded tree.
+		dangling := make(map[string]bool)
+		for _, pair := range [][]string{
+			{`supArc`, s.R_SupArc}, {`c-supArc`, s.RC_SupArc},
+			{`topArc`, s.R_TopArc}, {`c-topArc`, s.RC_TopArc},
+			{`minArc`, s.R_MinArc}, {`c-minArc`, s.RC_MinArc},
+			{`maxArc`, s.R_MaxArc}, {`c-maxArc`, s.RC_MaxArc},
+			{`leftArc`, s.R_LeftArc}, {`rightArc`, s.R_RightArc},
+		} {
+			if len(pair[1]) > 0 && root.findDN(pair[1]).IsZero() &&
+				!(known[0] && eq(pair[1], want[0])) && !(known[1] && eq(pair[1], want[1])) {
+				dangling[pair[0]] = true
+				fault(pair[0], pair[1], ``, DanglingSpatialDN)
+			}
+		}
+
+		for i, pair := range [][]string{
+			{`supArc`, s.R_SupArc},
+			{`topArc`, s.R_TopArc},
+			{`minArc`, s.R_MinArc},
+			{`maxArc`, s.R_MaxArc},
+			{`leftArc`, s.R_LeftArc},
+			{`rightArc`, s.R_RightArc},
+		} {
+			if len(pair[1]) == 0 || !known[i] || dangling[pair[0]] || eq(pair[1], want[i]) {
+				continue
+			}
+
+			kind := WrongSpatialArc
+			switch i {
+			case 1:
+				kind = NonRootTopArc
+			case 2, 3:
+				kind = StaleSpatialArc
+			}
+			fault(pair[0], pair[1], want[i], kind)
+		}
+
+		r.checkSubArc(root, fault)
+	}
+
+	K := r.Children()
+	for i := 0; i < K.Len(); i++ {
+		K.Index(i).checkSpatial(root, faults)
+	}
+}
+
+/*
+spatialDN returns the DN of the registration whose numeric OID is formed by
+the first N arcs of the receiver's own numeric OID, or by all but the last
+-N arcs if N is negative, alongside a Boolean value indicative of success.
+The DN is derived from the numeric OID alone, thus the registration need not
+be loaded.
+
+The numeric OID is that of the "[dotNotation]" value of the receiver, else
+that conveyed through the receiver's DN.
+
+[dotNotation]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.2
+*/
+func (r *Registration) spatialDN(n int) (dn string, ok bool) {
+	prof := r.Profile()
+	bidx := prof.RegistrationSuffixEqual(r.DN())
+	if bidx < 0 {
+		return
+	}
+	base := prof.registrationBase(bidx)
+
+	dot := r.X680().DotNotation()
+	if len(dot) == 0 {
+		// gather the number forms of each
+		// RDN above the registration base.
+		rdns := rdnSequence(r.DN())
+		var arcs []string
+		for i := len(rdns) - len(rdnSequence(base)) - 1; i >= 0; i-- {
+			at, val, err := rdnAttributeValue(rdns[i])
+			if err != nil || !(eq(at, `n`) || eq(at, `dotNotation`)) {
+				return
+			}
+			arcs = append(arcs, val)
+		}
+		dot = dotJoin(arcs)
+	}
+
+	arcs := dotSplit(dot)
+	if n < 0 {
+		n += len(arcs)
+	}
+
+	if ok = 0 < n && n <= len(arcs); ok {
+		dn = prof.dotNotationDN(dotJoin(arcs[:n]), base)
+	}
+
+	return
+}
+
+/*
+dotNotationDN returns the DN of the registration bearing the input numeric
+OID beneath the input registration base, per the model of the receiver.
+*/
+func (r *DITProfile) dotNotationDN(dot, base string) (dn string) {
+	if r.Model() == TwoDimensional {
+		dn = `dotNotation=` + dot + `,` + base
+		return
+	}
+
+	arcs := dotSplit(dot)
+	for i := len(arcs) - 1; i >= 0; i-- {
+		dn += `n=` + arcs[i] + `,`
+	}
+	dn += base
+
+	return
+}
+
+func (r *Registration) checkSubArc(root *Registration, fault func(string, string, string, SpatialFaultKind)) {
+	subs := r.R_Spatial.R_SubArc
+	if len(subs) == 0 {
+		return
+	}
+
+	K := r.Children()
+	found := make(map[*Registration]bool)
+	for _, sub := range subs {
+		var reg *Registration
+		if isNumber(sub) {
+			reg = K.Get(sub)
+		} else {
+			reg = root.findDN(sub)
+		}
+
+		switch {
+		case reg.IsZero():
+			fault(`subArc`, sub, ``, DanglingSpatialDN)
+		case reg.Parent() != r:
+			fault(`subArc`, sub, ``, WrongSpatialArc)
+		default:
+			found[reg] = true
+		}
+	}
+
+	for i := 0; i < K.Len(); i++ {
+		if child := K.Index(i); !found[child] {
+			fault(`subArc`, ``, child.DN(), MissingSubArc)
+		}
+	}
+}
+
+/*
+sortedSiblings returns a number form ordered copy of the slices within
+the parent's children, to include the receiver. The parent's own slice
+order is not altered.
+*/
+func (r *Registration) sortedSiblings() Registrations {
+	return r.Parent().sortedChildren()
+}
+
+/*
+sortedChildren returns a number form ordered copy of the receiver's
+children. The receiver's own slice order is not altered.
+*/
+func (r *Registration) sortedChildren() (kids Registrations) {
+	if !r.IsZero() {
+		K := r.Children()
+		kids = make(Registrations, K.Len())
+		copy(kids, *K)
+		stabSort(&kids)
+	}
+
+	return
+}
+
+func (r *Registration) repairSpatial() {
+	r.clearAxes()
+	r.Children().SortByNumberForm(true)
+
+	// write the topArc expected by checkSpatial,
+	// falling back to the root of the tree.
+	top, ok := r.spatialDN(1)
+	if !ok {
+		top = r.DN()
+	}
+	r.Spatial().SetTopArc(top)
+
+	regs := Registrations{r}
+	regs.SetYAxes(true)
+	r.SetXAxes(true)
+}
+
+func (r *Registration) clearAxes() {
+	if s := r.R_Spatial; !s.IsZero() {
+		s.R_SupArc, s.R_TopArc = ``, ``
+		s.R_MinArc, s.R_MaxArc = ``, ``
+		s.R_LeftArc, s.R_RightArc = ``, ``
+		s.R_SubArc = nil
+	}
+
+	K := r.Children()
+	for i := 0; i < K.Len(); i++ {
+		K.Index(i).clearAxes()
+	}
+}
+
+/*
+NewSpatialSubentry returns a new instance of *[Subentry] alongside an error
+following an attempt to describe the sibling pool formed by the children of
+the receiver through collective "[spatialContext]" values, thereby sparing
+each sibling from bearing its own literal "[minArc]" and "[maxArc]" values.
+
+The return instance bears the following values:
+
+  - "[subtreeSpecification]" which selects only the immediate subordinates of the receiver (minimum and maximum of 1)
+  - "[c-supArc]", which references the receiver
+  - "[c-topArc]", which references the root registration, if known
+  - "[c-minArc]" and "[c-maxArc]", which reference the lowest and highest numbered children of the receiver
+
+The input string value cn is used as the common name of the new instance.
+
+By default, the receiver serves as the administrative point, meaning the new
+instance is named beneath the receiver and is added to the receiver's own
+[Subentries] instance. The specification base is then left empty, as the
+administrative point itself is the base.
+
+Alternatively, the DN of a superior administrative point, such as the
+registration base of the relevant *[DITProfile], may be supplied as the
+variadic admin input value. In this case, the new instance is named beneath
+said DN, the specification base is set to the RDN sequence which separates
+the receiver from the administrative point and the new instance is NOT added
+to the receiver's [Subentries] instance.
+
+This method is only applicable to the [ThreeDimensional] model, as sibling
+pools cannot be isolated by base distance within the [TwoDimensional] model.
+
+[spatialContext]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.5.11
+[subtreeSpecification]: https://datatracker.ietf.org/doc/html/rfc3672#section-2.3
+[c-supArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.22
+[c-topArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.24
+[minArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.27
+[c-minArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.28
+[maxArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.30
+[c-maxArc]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.31
+*/
+func (r *Registration) NewSpatialSubentry(cn string, admin ...string) (se *Subentry, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	} else if r.Profile().Model() != ThreeDimensional {
+		err = InvalidDimensionErr
+		return
+	}
+
+	dn := r.DN()
+	if len(dn) == 0 || len(cn) == 0 {
+		err = InvalidDNErr
+		return
+	}
+
+	kids := r.sortedChildren()
+	if len(kids) == 0 {
+		err = NoSubordinatesErr
+		return
+	}
+
+	var base string
+	adm, local := dn, true
+	if len(admin) > 0 && !eq(admin[0], dn) {
+		adm, local = admin[0], false
+		L := len(dn) - len(adm) - 1
+		if L < 1 || dn[L] != ',' || !eq(dn[L+1:], adm) {
+			err = InvalidDNErr
+			return
+		}
+		base = dn[:L]
+	}
+
+	se = r.Profile().NewSubentry()
+	se.SetDN(`cn=` + cn + `,` + adm)
+	se.SetCN(cn)
+
+	if err = se.SetSubtreeSpecification(SubtreeSpecification{
+		Base: LocalName(base),
+		Min:  BaseDistance(1),
+		Max:  BaseDistance(1),
+	}); err != nil {
+		se = nil
+		return
+	} else if local {
+		r.Subentries().Push(se)
+	}
+
+	spat := se.Spatial()
+	spat.SetCSupArc(dn)
+	spat.SetCMinArc(kids[0].DN())
+	spat.SetCMaxArc(kids[len(kids)-1].DN())
+	if top := r.topArcDN(); len(top) > 0 {
+		spat.SetCTopArc(top)
+	}
+
+	return
+}
+
+/*
+topArcDN returns the DN of the root registration above the receiver, to
+include the receiver itself if it is a root.
+*/
+func (r *Registration) topArcDN() (dn string) {
+	if r.IsRoot() {
+		dn = r.DN()
+	} else if dn = r.Spatial().TopArc(); len(dn) == 0 {
+		if dn = r.Spatial().CTopArc(); len(dn) == 0 {
+			if root := r.treeRoot(); root.IsRoot() {
+				dn = root.DN()
+			}
+		}
+	}
+
+	return
+}
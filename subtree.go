@@ -1,9 +1,5 @@
 package radir
 
-import (
-	"errors"
-)
-
 /*
 SubtreeSpecification implements the Subtree Specification construct.
 
@@ -53,120 +49,443 @@ type SubtreeSpecification struct {
 
 /*
 NewSubtreeSpecification returns an instance of [SubtreeSpecification] alongside
-an error following an attempt to parse x.
+an error following an attempt to parse raw.
+
+Components must appear in the order prescribed by [Appendix A of RFC 3672].
+For compatibility, specific exclusions which delimit the chop keyword and
+the [LocalName] by whitespace rather than a colon are also accepted.
+
+Parse errors are returned as instances of *[SubtreeSpecificationError],
+which report the byte offset at which parsing failed as well as the token
+that was expected. A comma must separate each component.
+
+[Appendix A of RFC 3672]: https://datatracker.ietf.org/doc/html/rfc3672#appendix-A
 */
 func NewSubtreeSpecification(raw string) (ss SubtreeSpecification, err error) {
-	if len(raw) < 2 {
+	if len(raw) == 0 {
 		return
 	}
 
-	if raw[0] != '{' || raw[len(raw)-1] != '}' {
-		err = errors.New("SubtreeSpecification {} encapsulation error")
-		return
+	p := &subtreeParser{raw: raw}
+	if ss, err = p.specification(); err == nil {
+		p.skipSpace()
+		if p.pos < len(p.raw) {
+			err = p.fail(`end of input`)
+		}
+	}
+
+	return
+}
+
+/*
+NewSpecificationFilter returns an instance of [Refinement] based upon the
+string input value.
+
+Instances of [Refinement] are intended for assignment to the [SubtreeSpecification]
+"SpecificationFilter" field, though this is done automatically when using the
+[NewSubtreeSpecification] function.
+
+A zero instance is returned if the input value cannot be parsed. See the
+[ParseRefinement] function for a variant which returns an error.
+*/
+func NewSpecificationFilter(input string) (r Refinement) {
+	r, _ = ParseRefinement(input)
+	return
+}
+
+/*
+ParseRefinement returns an instance of [Refinement] alongside an error
+following an attempt to parse input. Parse errors are returned as instances
+of *[SubtreeSpecificationError].
+*/
+func ParseRefinement(input string) (r Refinement, err error) {
+	p := &subtreeParser{raw: input}
+	p.skipSpace()
+	if r, err = p.refinement(); err == nil {
+		p.skipSpace()
+		if p.pos < len(p.raw) {
+			err = p.fail(`end of input`)
+		}
+	}
+
+	return
+}
+
+/*
+SubtreeSpecificationError describes a failure to parse a subtree specification
+or specification filter.
+*/
+type SubtreeSpecificationError struct {
+	Offset   int    // byte offset of the offending input
+	Expected string // description of the expected token
+	Found    string // offending input, or zero at end of input
+}
+
+/*
+Error returns the string representation of the receiver instance.
+*/
+func (r *SubtreeSpecificationError) Error() string {
+	found := `end of input`
+	if len(r.Found) > 0 {
+		found = `'` + r.Found + `'`
+	}
+
+	return sprintf("subtreeSpecification: expected %s at offset %d, found %s",
+		r.Expected, r.Offset, found)
+}
+
+/*
+Unwrap returns [SubtreeSpecificationErr], such that instances of this type
+satisfy [errors.Is] when compared with said error.
+*/
+func (r *SubtreeSpecificationError) Unwrap() error {
+	return SubtreeSpecificationErr
+}
+
+/*
+subtreeParser implements a recursive-descent parser for the GSER encoding
+of subtree specifications per Appendix A of RFC 3672.
+*/
+type subtreeParser struct {
+	raw string
+	pos int
+}
+
+/*
+ssComponents contains the subtree specification component identifiers in
+their mandatory order of appearance.
+*/
+var ssComponents []string = []string{
+	`base`,
+	`specificExclusions`,
+	`minimum`,
+	`maximum`,
+	`specificationFilter`,
+}
+
+func (r *subtreeParser) fail(expected string) error {
+	var found string
+	if r.pos < len(r.raw) {
+		found = r.raw[r.pos:]
+		if len(found) > 16 {
+			found = found[:16] + `...`
+		}
+	}
+
+	return &SubtreeSpecificationError{
+		Offset:   r.pos,
+		Expected: expected,
+		Found:    found,
 	}
+}
+
+func (r *subtreeParser) skipSpace() (n int) {
+	for r.pos < len(r.raw) && r.raw[r.pos] == ' ' {
+		r.pos++
+		n++
+	}
+
+	return
+}
 
-	if raw = trimS(raw[1 : len(raw)-1]); raw == `{}` {
+func (r *subtreeParser) peek(ch byte) bool {
+	return r.pos < len(r.raw) && r.raw[r.pos] == ch
+}
+
+func (r *subtreeParser) expect(ch byte) (err error) {
+	if !r.peek(ch) {
+		err = r.fail(`'` + string(ch) + `'`)
+	} else {
+		r.pos++
+	}
+
+	return
+}
+
+/*
+word returns, but does not consume, the run of keychar bytes at the
+current position.
+*/
+func (r *subtreeParser) word() string {
+	end := r.pos
+	for end < len(r.raw) {
+		if ch := r.raw[end]; !(isKeychar(ch) || ch == '.') {
+			break
+		}
+		end++
+	}
+
+	return r.raw[r.pos:end]
+}
+
+func (r *subtreeParser) specification() (ss SubtreeSpecification, err error) {
+	if err = r.expect('{'); err != nil {
 		return
 	}
 
-	var ranges map[string][]int = make(map[string][]int, 0)
+	next := 0
+	for err == nil {
+		r.skipSpace()
+		if r.peek('}') {
+			r.pos++
+			break
+		}
 
-	var pos int
-	if begin := idxs(raw, `base `); begin != -1 {
-		var end int
-		begin += 5
-		if ss.Base, end, err = subtreeBase(raw[begin:]); err != nil {
-			return
+		// every component after the first must
+		// be preceded by a comma.
+		if next > 0 {
+			if err = r.expect(','); err != nil {
+				break
+			}
+			r.skipSpace()
+		}
+
+		word := r.word()
+		idx := -1
+		for i := next; i < len(ssComponents) && idx == -1; i++ {
+			if word == ssComponents[i] {
+				idx = i
+			}
+		}
+
+		if idx == -1 {
+			expected := `'}'`
+			if next < len(ssComponents) {
+				expected = `"` + join(ssComponents[next:], `", "`) + `" or '}'`
+			}
+			err = r.fail(expected)
+			break
+		}
+
+		r.pos += len(word)
+		if r.skipSpace() == 0 {
+			err = r.fail(`space`)
+			break
 		}
-		pos += begin
-		end += pos + 1
-		ranges[`base`] = []int{begin, end}
+
+		switch idx {
+		case 0:
+			var base string
+			base, err = r.localName()
+			ss.Base = LocalName(base)
+		case 1:
+			ss.SpecificExclusions, err = r.exclusions()
+		case 2:
+			ss.Min, err = r.baseDistance()
+		case 3:
+			ss.Max, err = r.baseDistance()
+		case 4:
+			ss.SpecificationFilter, err = r.refinement()
+		}
+
+		next = idx + 1
+	}
+
+	return
+}
+
+/*
+localName parses a dquote-encapsulated string, in which any literal
+dquote is escaped by way of a second dquote.
+*/
+func (r *subtreeParser) localName() (name string, err error) {
+	if err = r.expect('"'); err != nil {
+		return
 	}
 
-	if begin := idxs(raw, `specificExclusions `); begin != -1 {
-		var end int
-		begin += 19
-		if ss.SpecificExclusions, end, err = subtreeExclusions(raw, begin); err != nil {
+	bld := newBuilder()
+	for {
+		if r.pos >= len(r.raw) {
+			err = r.fail(`closing '"'`)
 			return
 		}
-		end = begin + end
-		ranges[`specificExclusions`] = []int{begin, end}
+
+		ch := r.raw[r.pos]
+		r.pos++
+		if ch == '"' {
+			if !r.peek('"') {
+				break
+			}
+			r.pos++
+		}
+		bld.WriteByte(ch)
+	}
+
+	name = bld.String()
+
+	return
+}
+
+func (r *subtreeParser) baseDistance() (bd BaseDistance, err error) {
+	start := r.pos
+	for r.pos < len(r.raw) && isDigit(rune(r.raw[r.pos])) {
+		r.pos++
+	}
+
+	num := r.raw[start:r.pos]
+	if len(num) == 0 || (len(num) > 1 && num[0] == '0') {
+		r.pos = start
+		err = r.fail(`INTEGER-0-MAX`)
+		return
 	}
 
-	if begin := idxs(raw, `minimum `); begin != -1 {
-		var end int
-		begin += 8
-		if ss.Min, end, err = subtreeMinMax(raw, begin); err != nil {
+	var n int
+	if n, err = atoi(num); err != nil {
+		r.pos = start
+		err = r.fail(`INTEGER-0-MAX`)
+		return
+	}
+	bd = BaseDistance(n)
+
+	return
+}
+
+func (r *subtreeParser) exclusions() (excl SpecificExclusions, err error) {
+	if err = r.expect('{'); err != nil {
+		return
+	}
+
+	excl = make(SpecificExclusions, 0)
+	r.skipSpace()
+	if r.peek('}') {
+		r.pos++
+		return
+	}
+
+	for err == nil {
+		var ex SpecificExclusion
+		switch word := r.word(); word {
+		case `chopBefore`, `chopAfter`:
+			ex.After = word == `chopAfter`
+			r.pos += len(word)
+		default:
+			err = r.fail(`"chopBefore" or "chopAfter"`)
 			return
 		}
-		end = begin + end
-		ranges[`minimum`] = []int{begin, end}
-	}
 
-	if begin := idxs(raw, `maximum `); begin != -1 {
-		var end int
-		begin += 8
-		if ss.Max, end, err = subtreeMinMax(raw, begin); err != nil {
+		// RFC 3672 prescribes a colon, but tolerate
+		// legacy whitespace delimitation as well.
+		r.skipSpace()
+		if r.peek(':') {
+			r.pos++
+		}
+		r.skipSpace()
+
+		var name string
+		if name, err = r.localName(); err != nil {
 			return
 		}
-		end = begin + end
-		ranges[`maximum`] = []int{begin, end}
-	}
+		ex.Name = LocalName(name)
+		excl = append(excl, ex)
 
-	if begin := idxs(raw, `specificationFilter `); begin != -1 {
-		ss.SpecificationFilter = NewSpecificationFilter(raw[begin+20:])
-		ranges[`specificationFilter`] = []int{begin, len(raw) - 1}
+		r.skipSpace()
+		if r.peek('}') {
+			r.pos++
+			break
+		} else if err = r.expect(','); err != nil {
+			err = r.fail(`',' or '}'`)
+		}
+		r.skipSpace()
 	}
 
 	return
 }
 
-/*
-NewSpecificationFilter returns an instance of [Refinement] based upon the
-string input value.
+func (r *subtreeParser) refinement() (ref Refinement, err error) {
+	word := r.word()
+	switch word {
+	case `item`, `and`, `or`, `not`:
+		r.pos += len(word)
+	default:
+		err = r.fail(`"item", "and", "or" or "not"`)
+		return
+	}
 
-Instances of [Refinement] are intended for assignment to the [SubtreeSpecification]
-"SpecificationFilter" field, though this is done automatically when using the
-[NewSubtreeSpecification] function.
-*/
-func NewSpecificationFilter(input string) (r Refinement) {
-	if hasPfx(input, "and:") {
-		r.And = parseRefinementList(trimPfx(input, "and:"))
-	} else if hasPfx(input, "or:") {
-		r.Or = parseRefinementList(trimPfx(input, "or:"))
-	} else if hasPfx(input, "not:") {
-		notRefinement := NewSpecificationFilter(trimPfx(input, "not:"))
-		r.Not = &notRefinement
-	} else if hasPfx(input, "item:") {
-		r.Item = trimPfx(input, "item:")
+	if err = r.expect(':'); err != nil {
+		return
+	}
+
+	switch word {
+	case `item`:
+		oid := r.word()
+		if !isOIDOrDescr(oid) {
+			err = r.fail(`OBJECT-IDENTIFIER`)
+			return
+		}
+		r.pos += len(oid)
+		ref.Item = oid
+	case `and`:
+		ref.And, err = r.refinements()
+	case `or`:
+		ref.Or, err = r.refinements()
+	case `not`:
+		var not Refinement
+		if not, err = r.refinement(); err == nil {
+			ref.Not = &not
+		}
 	}
 
 	return
 }
 
-func parseRefinementList(input string) (refs []Refinement) {
+func (r *subtreeParser) refinements() (refs []Refinement, err error) {
+	if err = r.expect('{'); err != nil {
+		return
+	}
+
+	for err == nil {
+		r.skipSpace()
 
-	t := trim(input, "{} ")
+		var ref Refinement
+		if ref, err = r.refinement(); err != nil {
+			return
+		}
+		refs = append(refs, ref)
 
-	var partStart, braceLevel int
-	for i, r := range t {
-		if r == ',' && braceLevel == 0 {
-			refs = append(refs, NewSpecificationFilter(t[partStart:i]))
-			partStart = i + 1
-		} else if r == '{' {
-			braceLevel++
-		} else if r == '}' {
-			braceLevel--
+		r.skipSpace()
+		if r.peek('}') {
+			r.pos++
+			break
+		} else if !r.peek(',') {
+			err = r.fail(`',' or '}'`)
 		}
+		r.pos++
 	}
 
-	if partStart < len(t) {
-		refs = append(refs, NewSpecificationFilter(t[partStart:]))
+	return
+}
+
+/*
+isOIDOrDescr returns a Boolean value indicative of whether the input value
+is a numeric OID or a descriptor (keystring), per RFC 4512.
+*/
+func isOIDOrDescr(x string) (ok bool) {
+	if len(x) == 0 {
+		return
+	}
+
+	if '0' <= x[0] && x[0] <= '9' {
+		ok = IsNumericOID(x)
+		return
+	}
+
+	ok = isKeychar(x[0]) && x[0] != '-'
+	for i := 1; i < len(x) && ok; i++ {
+		ok = isKeychar(x[i])
 	}
 
 	return
 }
 
+/*
+isKeychar returns a Boolean value indicative of whether ch is an ASCII
+letter, digit or hyphen.
+*/
+func isKeychar(ch byte) bool {
+	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') ||
+		('0' <= ch && ch <= '9') || ch == '-'
+}
+
 /*
 SpecificExclusions implements the Subtree Specification exclusions construct.
 
@@ -203,6 +522,15 @@ LocalName implements an RDNSequence.
 */
 type LocalName string
 
+/*
+String returns the dquote-encapsulated string representation of the
+receiver instance, in which any literal dquote is escaped by way of a
+second dquote.
+*/
+func (r LocalName) String() string {
+	return `"` + replaceAll(string(r), `"`, `""`) + `"`
+}
+
 /*
 String returns the string representation of the receiver instance.
 */
@@ -225,108 +553,10 @@ String returns the string representation of the receiver instance.
 func (r SpecificExclusion) String() (s string) {
 	if len(r.Name) > 0 {
 		if r.After {
-			s = `chopAfter ` + `"` + string(r.Name) + `"`
+			s = `chopAfter:` + r.Name.String()
 		} else {
-			s = `chopBefore ` + `"` + string(r.Name) + `"`
-		}
-	}
-
-	return
-}
-
-func subtreeExclusions(raw string, begin int) (excl SpecificExclusions, end int, err error) {
-	end = -1
-
-	if raw[begin] != '{' {
-		err = errors.New("Bad exclusion encapsulation")
-		return
-	}
-
-	var pos int
-	if pos, end, err = deconstructExclusions(raw, begin); err != nil {
-		return
-	}
-
-	values := fields(raw[pos:end])
-	excl = make(SpecificExclusions, 0)
-
-	for i := 0; i < len(values); i += 2 {
-		var ex SpecificExclusion
-		if !strInSlice(values[i], []string{`chopBefore`, `chopAfter`}) {
-			err = errors.New("Unexpected key '" + values[i] + "'")
-			break
-
+			s = `chopBefore:` + r.Name.String()
 		}
-		ex.After = values[i] == `chopAfter`
-
-		localName := trim(trimR(values[i+1], `,`), `"`)
-		//if err = isSafeUTF8(localName); err == nil {
-		ex.Name = LocalName(localName)
-		excl = append(excl, ex)
-		//}
-	}
-
-	return
-}
-
-func deconstructExclusions(raw string, begin int) (pos, end int, err error) {
-	pos = -1
-	if idx := idxs(raw[begin:], `chop`); idx != -1 {
-		var (
-			before int = -1
-			after  int = -1
-		)
-
-		if hasPfx(raw[begin+idx+4:], `Before`) {
-			before = begin + idx
-		}
-
-		if hasPfx(raw[begin+idx+4:], `After`) {
-			after = begin + idx
-		}
-
-		if after == -1 && before > after {
-			pos = before
-		} else if before == -1 && before < after {
-			pos = after
-		}
-	}
-
-	if pos == -1 {
-		err = errors.New("No chop directive found in value")
-		return
-	}
-
-	for i, char := range raw[pos:] {
-		switch char {
-		case '}':
-			end = pos + i
-			break
-		}
-	}
-
-	return
-}
-
-func subtreeMinMax(raw string, begin int) (minmax BaseDistance, end int, err error) {
-	end = -1
-
-	var (
-		max string
-		m   int
-	)
-
-	for i := 0; i < len(raw[begin:]); i++ {
-		if isDigit(rune(raw[begin+i])) {
-			max += string(raw[begin+i])
-			continue
-		}
-		break
-	}
-
-	if m, err = atoi(max); err == nil {
-		minmax = BaseDistance(m)
-		end = len(max)
 	}
 
 	return
@@ -338,7 +568,7 @@ String returns the string representation of the receiver instance.
 func (r SubtreeSpecification) String() (s string) {
 	var _s []string
 	if len(r.Base) > 0 {
-		_s = append(_s, `base `+`"`+string(r.Base)+`"`)
+		_s = append(_s, `base `+r.Base.String())
 	}
 
 	if x := r.SpecificExclusions; len(x) > 0 {
@@ -433,29 +663,379 @@ type Refinement struct {
 	Not  *Refinement
 }
 
-func subtreeBase(raw string) (base LocalName, end int, err error) {
-	end = -1
-	if len(raw) == 0 {
-		err = errors.New("Empty LocalName")
+/*
+Includes returns a Boolean value indicative of whether the input value
+falls within the subtree described by the receiver instance, alongside
+an error.
+
+The admin input value is the DN of the administrative point to which the
+receiver is relative, such as "ou=Registrations,o=rA". The input value x
+may be a string DN or a *[Registration] instance.
+
+Evaluation honors the following components, per [clause 12.3 of ITU-T
+Rec. X.501]:
+
+  - Base, which is appended to admin to form the subtree's base DN; a zero base denotes the administrative point itself
+  - SpecificExclusions, where chopBefore excludes the named entry and its subordinates, while chopAfter excludes only the subordinates
+  - Min and Max base distances, where a Max of zero denotes no limit
+  - SpecificationFilter, whose "item:" values are matched against the objectClass values of the candidate using descriptors or numeric OIDs
+
+Note that objectClass superclass chains are not considered when evaluating
+"item:" refinements.
+
+When x is a string DN and the receiver bears a SpecificationFilter, the
+[MissingObjectClassesErr] error is returned, as refinements cannot be
+evaluated without the candidate's objectClass values.
+
+[clause 12.3 of ITU-T Rec. X.501]: https://www.itu.int/rec/T-REC-X.501
+*/
+func (r SubtreeSpecification) Includes(admin string, x any) (in bool, err error) {
+	var (
+		dn  string
+		ocs []string
+	)
+
+	switch tv := x.(type) {
+	case string:
+		dn = tv
+	case *Registration:
+		if tv.IsZero() {
+			err = NilRegistrationErr
+			return
+		}
+		dn = tv.DN()
+		ocs = tv.effectiveObjectClasses()
+	default:
+		err = UnsupportedInputTypeErr
+		return
+	}
+
+	if len(dn) == 0 {
+		err = InvalidDNErr
+		return
+	}
+
+	filtered := !r.SpecificationFilter.IsZero()
+	if filtered && len(ocs) == 0 {
+		err = MissingObjectClassesErr
+		return
+	}
+
+	base := rdnSequence(admin)
+	if len(r.Base) > 0 {
+		base = append(rdnSequence(string(r.Base)), base...)
+	}
+
+	rdns := rdnSequence(dn)
+	dist := len(rdns) - len(base)
+	if dist < 0 || !rdnSuffixEqual(rdns, base) {
+		return
+	}
+
+	if dist < int(r.Min) || (r.Max > 0 && dist > int(r.Max)) {
 		return
 	}
 
-	if raw[0] != '"' {
-		err = errors.New("Missing encapsulation (\") for LocalName")
+	for _, ex := range r.SpecificExclusions {
+		chop := append(rdnSequence(string(ex.Name)), base...)
+		if rdnSuffixEqual(rdns, chop) {
+			if !ex.After || len(rdns) > len(chop) {
+				return
+			}
+		}
+	}
+
+	in = !filtered || r.SpecificationFilter.Match(ocs...)
+
+	return
+}
+
+/*
+IsZero returns a Boolean value indicative of a nil receiver state.
+*/
+func (r Refinement) IsZero() bool {
+	return len(r.Item) == 0 && len(r.And) == 0 && len(r.Or) == 0 && r.Not == nil
+}
+
+/*
+Match returns a Boolean value indicative of whether the input objectClass
+values satisfy the receiver instance. Both descriptors and numeric OIDs are
+supported for the receiver's "item:" values as well as the input values,
+and case is not significant in the matching process.
+
+A zero receiver matches any input.
+*/
+func (r Refinement) Match(oc ...string) (match bool) {
+	switch {
+	case len(r.Item) > 0:
+		item := trimS(r.Item)
+		for i := 0; i < len(oc) && !match; i++ {
+			if match = eq(item, oc[i]); !match {
+				// unknown descriptors bear no OID,
+				// thus they must never match each
+				// other by that means.
+				oid := objectClassOID(item)
+				match = len(oid) > 0 && eq(oid, objectClassOID(oc[i]))
+			}
+		}
+	case len(r.And) > 0:
+		match = true
+		for i := 0; i < len(r.And) && match; i++ {
+			match = r.And[i].Match(oc...)
+		}
+	case len(r.Or) > 0:
+		for i := 0; i < len(r.Or) && !match; i++ {
+			match = r.Or[i].Match(oc...)
+		}
+	case r.Not != nil:
+		match = !r.Not.Match(oc...)
+	default:
+		match = true
+	}
+
+	return
+}
+
+/*
+objectClassOID returns the numeric OID of the input objectClass descriptor,
+if known to this package. Numeric OIDs are returned as-is, while unknown
+descriptors are returned as a zero string.
+*/
+func objectClassOID(oc string) (oid string) {
+	if IsNumericOID(oc) {
+		oid = oc
 		return
 	}
 
-	for i := 1; i < len(raw) && end == -1; i++ {
-		switch char := rune(raw[i]); char {
-		case '"':
-			end = i
+	for name, num := range ObjectClasses {
+		if eq(name, oc) {
+			oid = num
 			break
 		}
 	}
 
-	//if err = isSafeUTF8(raw[1:end]); err == nil {
-	base = LocalName(raw[1:end])
-	//}
+	return
+}
+
+/*
+rdnSequence returns the RDN components of the input DN, each of which is
+trimmed of surrounding whitespace.
+*/
+func rdnSequence(dn string) (rdns []string) {
+	if dn = trimS(dn); len(dn) > 0 {
+		rdns = splitUnescaped(dn, `,`, `\`)
+		for i := 0; i < len(rdns); i++ {
+			rdns[i] = trimS(rdns[i])
+		}
+	}
+
+	return
+}
+
+/*
+rdnSuffixEqual returns a Boolean value indicative of whether the RDN
+sequence sfx is the trailing portion of the RDN sequence rdns. Case is
+not significant in the matching process.
+*/
+func rdnSuffixEqual(rdns, sfx []string) (ok bool) {
+	if offset := len(rdns) - len(sfx); offset >= 0 {
+		ok = true
+		for i := 0; i < len(sfx) && ok; i++ {
+			ok = eq(rdns[offset+i], sfx[i])
+		}
+	}
+
+	return
+}
+
+/*
+SubtreeSpecificationBuilder implements a convenient means of composing an
+instance of [SubtreeSpecification] without hand-writing its string form.
+
+Instances of this type are created using the [NewSubtreeSpecificationBuilder]
+function. Each method returns the receiver, thus allowing calls to be chained,
+while [SubtreeSpecificationBuilder.Build] returns the completed instance:
+
+	ss, err := NewSubtreeSpecificationBuilder().
+		Base(`n=3,n=1`).
+		ChopAfter(`n=6`).
+		Minimum(1).
+		Filter(AndRefinement(
+			ItemRefinement(`registration`),
+			NotRefinement(ItemRefinement(`rootArc`)))).
+		Build()
+
+The string form of the result, which may be submitted to [Subentry.SetSubtreeSpecification],
+is canonical and will re-parse identically through [NewSubtreeSpecification].
+*/
+type SubtreeSpecificationBuilder struct {
+	ss SubtreeSpecification
+}
+
+/*
+NewSubtreeSpecificationBuilder returns a freshly initialized instance of
+*[SubtreeSpecificationBuilder].
+*/
+func NewSubtreeSpecificationBuilder() *SubtreeSpecificationBuilder {
+	return &SubtreeSpecificationBuilder{}
+}
+
+/*
+Base assigns the input RDN sequence as the base of the specification,
+relative to the administrative point.
+*/
+func (r *SubtreeSpecificationBuilder) Base(base string) *SubtreeSpecificationBuilder {
+	r.ss.Base = LocalName(base)
+	return r
+}
+
+/*
+ChopBefore appends a chopBefore exclusion, which excludes the named entry
+(relative to the base) and its subordinates.
+*/
+func (r *SubtreeSpecificationBuilder) ChopBefore(name string) *SubtreeSpecificationBuilder {
+	r.ss.SpecificExclusions = append(r.ss.SpecificExclusions,
+		SpecificExclusion{Name: LocalName(name)})
+	return r
+}
+
+/*
+ChopAfter appends a chopAfter exclusion, which excludes the subordinates
+of the named entry (relative to the base), but not the entry itself.
+*/
+func (r *SubtreeSpecificationBuilder) ChopAfter(name string) *SubtreeSpecificationBuilder {
+	r.ss.SpecificExclusions = append(r.ss.SpecificExclusions,
+		SpecificExclusion{Name: LocalName(name), After: true})
+	return r
+}
+
+/*
+Minimum assigns the minimum base distance of the specification.
+*/
+func (r *SubtreeSpecificationBuilder) Minimum(min int) *SubtreeSpecificationBuilder {
+	r.ss.Min = BaseDistance(min)
+	return r
+}
+
+/*
+Maximum assigns the maximum base distance of the specification. A value
+of zero denotes no limit.
+*/
+func (r *SubtreeSpecificationBuilder) Maximum(max int) *SubtreeSpecificationBuilder {
+	r.ss.Max = BaseDistance(max)
+	return r
+}
+
+/*
+Filter assigns the input [Refinement] as the specificationFilter of the
+specification. See [ItemRefinement], [AndRefinement], [OrRefinement] and
+[NotRefinement].
+*/
+func (r *SubtreeSpecificationBuilder) Filter(ref Refinement) *SubtreeSpecificationBuilder {
+	r.ss.SpecificationFilter = ref
+	return r
+}
+
+/*
+Build returns the composed instance of [SubtreeSpecification] alongside an
+error following a call of [SubtreeSpecification.Valid].
+*/
+func (r *SubtreeSpecificationBuilder) Build() (ss SubtreeSpecification, err error) {
+	if err = r.ss.Valid(); err == nil {
+		ss = r.ss
+	}
+
+	return
+}
+
+/*
+Valid returns an error if the receiver instance is malformed or self
+contradictory, such as bearing a negative base distance, a maximum which
+is less than the minimum, an unnamed exclusion or a malformed refinement.
+*/
+func (r SubtreeSpecification) Valid() (err error) {
+	switch {
+	case r.Min < 0 || r.Max < 0:
+		err = wrapErr(SubtreeSpecificationErr, "negative base distance")
+	case r.Max > 0 && r.Max < r.Min:
+		err = wrapErr(SubtreeSpecificationErr, "maximum is less than minimum")
+	default:
+		for _, ex := range r.SpecificExclusions {
+			if len(ex.Name) == 0 {
+				err = wrapErr(SubtreeSpecificationErr, "unnamed exclusion")
+				return
+			}
+		}
+
+		if !r.SpecificationFilter.IsZero() {
+			err = r.SpecificationFilter.valid()
+		}
+	}
+
+	return
+}
+
+func (r Refinement) valid() (err error) {
+	var set int
+	for _, ok := range []bool{
+		len(r.Item) > 0,
+		len(r.And) > 0,
+		len(r.Or) > 0,
+		r.Not != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+
+	switch {
+	case set != 1:
+		err = wrapErr(SubtreeSpecificationErr, "refinement must bear exactly one of item, and, or, not")
+	case len(r.Item) > 0:
+		if !isOIDOrDescr(r.Item) {
+			err = wrapErr(SubtreeSpecificationErr, "invalid item '"+r.Item+"'")
+		}
+	case r.Not != nil:
+		err = r.Not.valid()
+	default:
+		for _, ref := range append(r.And, r.Or...) {
+			if err = ref.valid(); err != nil {
+				break
+			}
+		}
+	}
 
 	return
 }
+
+/*
+ItemRefinement returns an "item:" instance of [Refinement] bearing the input
+objectClass descriptor or numeric OID.
+*/
+func ItemRefinement(oc string) Refinement {
+	return Refinement{Item: oc}
+}
+
+/*
+AndRefinement returns an "and:" instance of [Refinement] bearing the input
+[Refinement] instances.
+*/
+func AndRefinement(refs ...Refinement) Refinement {
+	return Refinement{And: refs}
+}
+
+/*
+OrRefinement returns an "or:" instance of [Refinement] bearing the input
+[Refinement] instances.
+*/
+func OrRefinement(refs ...Refinement) Refinement {
+	return Refinement{Or: refs}
+}
+
+/*
+NotRefinement returns a "not:" instance of [Refinement] which negates the
+input [Refinement] instance.
+*/
+func NotRefinement(ref Refinement) Refinement {
+	return Refinement{Not: &ref}
+}
@@ -1,13 +1,18 @@
 package radir
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestSubtreeSpecification(t *testing.T) {
 	// Verify parsing of valid string-based SubSpec values
 	for idx, raw := range []string{
 		`{minimum 1, maximum 1}`,
 		`{base "n=1,n=4,n=1,n=6,n=3,n=1", minimum 1}`,
-		`{specificExclusions { chopBefore "n=14", chopAfter "n=555", chopAfter "n=74,n=6" }, minimum 1, maximum 1}`,
+		`{specificExclusions { chopBefore:"n=14", chopAfter:"n=555", chopAfter:"n=74,n=6" }, minimum 1, maximum 1}`,
+		`{base "cn=""quoted""", specificExclusions { chopBefore:"n=1" }}`,
 		`{specificationFilter and:{item:1.3.6.1.4.1,or:{item:cn,item:2.5.4.7}}}`,
 		`{base "n=1,n=4,n=1,n=6,n=3,n=1", minimum 1, maximum 1, specificationFilter and:{item:1.3.6.1.4.1,or:{item:cn,item:2.5.4.7}}}`,
 		`{base "n=1,n=4,n=1,n=6,n=3,n=1", minimum 1, maximum 1, specificationFilter and:{item:1.3.6.1.4.1,not:item:1.3.6.1.5.5,or:{item:cn,item:2.5.4.7}}}`,
@@ -23,26 +28,130 @@ func TestSubtreeSpecification(t *testing.T) {
 	}
 }
 
+func TestSubtreeSpecification_legacy(t *testing.T) {
+	// Whitespace-delimited exclusions are accepted,
+	// but are rendered in canonical (colon) form.
+	raw := `{specificExclusions { chopBefore "n=14", chopAfter "n=555" }, minimum 1}`
+	want := `{specificExclusions { chopBefore:"n=14", chopAfter:"n=555" }, minimum 1}`
+	if v, err := NewSubtreeSpecification(raw); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+	} else if got := v.String(); got != want {
+		t.Errorf("%s failed:\nwant: %s\ngot:  %s", t.Name(), want, got)
+	}
+}
+
+func TestSubtreeSpecification_parseErrors(t *testing.T) {
+	for idx, tst := range []struct {
+		raw      string
+		offset   int
+		expected string
+	}{
+		{`{`, 1, `"base", "specificExclusions", "minimum", "maximum", "specificationFilter" or '}'`},
+		{`?{}`, 0, `'{'`},
+		{`{}}`, 2, `end of input`},
+		{`{base "n=1`, 10, `closing '"'`},
+		{`{base n=1}`, 6, `'"'`},
+		{`{minimum A}`, 9, `INTEGER-0-MAX`},
+		{`{minimum 01}`, 9, `INTEGER-0-MAX`},
+		{`{minimum 1, base "n=1"}`, 12, `"maximum", "specificationFilter" or '}'`},
+		{`{maximum"1"}`, 8, `space`},
+		{`{specificExclusions {hello "test"}}`, 21, `"chopBefore" or "chopAfter"`},
+		{`{specificExclusions { chopBefore:"n=1" chopAfter:"n=2" }}`, 39, `',' or '}'`},
+		{`{specificationFilter and:{item:1.2.3.4,or:}}`, 42, `'{'`},
+		{`{specificationFilter item:1.}`, 26, `OBJECT-IDENTIFIER`},
+		{`{specificationFilter nand:{}}`, 21, `"item", "and", "or" or "not"`},
+		{`{specificationFilter not:item:x-y item:z}`, 34, `','`},
+		{`{minimum 1 maximum 2}`, 11, `','`},
+	} {
+		_, err := NewSubtreeSpecification(tst.raw)
+		var perr *SubtreeSpecificationError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s[%d] failed: want *SubtreeSpecificationError, got %T (%v)",
+				t.Name(), idx, err, err)
+		} else if perr.Offset != tst.offset || perr.Expected != tst.expected {
+			t.Errorf("%s[%d] failed: want %s at %d, got %s at %d",
+				t.Name(), idx, tst.expected, tst.offset, perr.Expected, perr.Offset)
+		} else if perr.Error() == `` {
+			t.Errorf("%s[%d] failed: zero error string", t.Name(), idx)
+		} else if !errors.Is(err, SubtreeSpecificationErr) {
+			t.Errorf("%s[%d] failed: error does not match SubtreeSpecificationErr", t.Name(), idx)
+		}
+	}
+
+	if _, err := ParseRefinement(`item:arc,`); err == nil {
+		t.Errorf("%s failed: expected trailing input error", t.Name())
+	}
+}
+
+func TestSubtreeSpecificationBuilder(t *testing.T) {
+	ss, err := NewSubtreeSpecificationBuilder().
+		Base(`n=3,n=1`).
+		ChopBefore(`n=2`).
+		ChopAfter(`n=6`).
+		Minimum(1).
+		Maximum(4).
+		Filter(AndRefinement(
+			ItemRefinement(`registration`),
+			NotRefinement(ItemRefinement(`rootArc`)),
+			OrRefinement(ItemRefinement(`arc`), ItemRefinement(`1.3.6.1.4.1.56521.101.2.5.3`)))).
+		Build()
+	if err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	}
+
+	want := `{base "n=3,n=1", specificExclusions { chopBefore:"n=2", chopAfter:"n=6" }, ` +
+		`minimum 1, maximum 4, specificationFilter and:{item:registration,not:item:rootArc,` +
+		`or:{item:arc,item:1.3.6.1.4.1.56521.101.2.5.3}}}`
+	if got := ss.String(); got != want {
+		t.Errorf("%s failed:\nwant: %s\ngot:  %s", t.Name(), want, got)
+		return
+	}
+
+	// Verify canonical round-trip
+	if reparsed, err := NewSubtreeSpecification(want); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if got := reparsed.String(); got != want {
+		t.Errorf("%s failed: round-trip mismatch:\nwant: %s\ngot:  %s", t.Name(), want, got)
+		return
+	}
+
+	for idx, bld := range []*SubtreeSpecificationBuilder{
+		NewSubtreeSpecificationBuilder().Minimum(-1),
+		NewSubtreeSpecificationBuilder().Minimum(3).Maximum(2),
+		NewSubtreeSpecificationBuilder().ChopAfter(``),
+		NewSubtreeSpecificationBuilder().Filter(ItemRefinement(`1.`)),
+		NewSubtreeSpecificationBuilder().Filter(NotRefinement(ItemRefinement(`-x`))),
+		NewSubtreeSpecificationBuilder().Filter(AndRefinement(ItemRefinement(`arc`), Refinement{})),
+		NewSubtreeSpecificationBuilder().Filter(Refinement{Item: `arc`, Or: []Refinement{{Item: `x`}}}),
+	} {
+		if _, err = bld.Build(); !errors.Is(err, SubtreeSpecificationErr) {
+			t.Errorf("%s[%d] failed: expected validity error, got %v", t.Name(), idx, err)
+			return
+		}
+	}
+
+	sub := myDedicatedProfile.NewSubentry()
+	if err = sub.SetSubtreeSpecification(NewSubtreeSpecificationBuilder().Maximum(1)); err != nil {
+		t.Errorf("%s failed: %v", t.Name(), err)
+		return
+	} else if got := sub.SubtreeSpecification(); len(got) != 1 || got[0] != `{maximum 1}` {
+		t.Errorf("%s failed: unexpected subtreeSpecification %v", t.Name(), got)
+		return
+	} else if err = sub.SetSubtreeSpecification(NewSubtreeSpecificationBuilder().Maximum(-1)); err == nil {
+		t.Errorf("%s failed: expected validity error", t.Name())
+		return
+	}
+}
+
 func TestSubtreeSpecification_codecov(t *testing.T) {
 	// largely focused on avoiding panics.
 
-	subtreeBase(``)            // zero
-	subtreeBase(`n=1,n=3,n=6`) // missing quotes
-	subtreeBase(`"n=1,n=3,n=6"`)
-
 	spexcl := SpecificExclusions{}
 	_ = spexcl.String()
 
-	parseRefinementList(`{and:{item:1.2.3.4,or:}{item:1.2.3.4}}}`)
-
-	subtreeExclusions(`{}`, 0)
-	subtreeExclusions(`chopBefore "n=1"`, 0)
-	subtreeExclusions(`{chopBefore "n=1}"`, 0)
-	subtreeExclusions(`{chopBeforee "n=1}"`, 0)
-
-	deconstructExclusions(``, 0)
-	deconstructExclusions(`chopBefore "n=1"`, 0)
-	deconstructExclusions(`chopAfter "n=1"`, 0)
+	NewSpecificationFilter(`and:{item:1.2.3.4,or:}{item:1.2.3.4}}}`)
 
 	_, _ = NewSubtreeSpecification(`{and:{item:1.2.3.4,or:}{item:1.2.3.4}}}`)
 	_, _ = NewSubtreeSpecification(`{`)
@@ -56,3 +165,122 @@ func TestSubtreeSpecification_codecov(t *testing.T) {
 	_, _ = NewSubtreeSpecification(`{maximum J}`)
 
 }
+
+func TestSubtreeSpecification_Includes(t *testing.T) {
+	admin := `ou=Registrations,o=rA`
+
+	for idx, tst := range []struct {
+		raw  string
+		dn   string
+		want bool
+	}{
+		{`{}`, admin, true},
+		{`{}`, `n=1,` + admin, true},
+		{`{}`, `n=1,ou=Elsewhere,o=rA`, false},
+		{`{minimum 1}`, admin, false},
+		{`{minimum 1, maximum 1}`, `n=1,` + admin, true},
+		{`{minimum 1, maximum 1}`, `n=3,n=1,` + admin, false},
+		{`{base "n=3,n=1", minimum 1, maximum 1}`, `n=6,n=3,n=1,` + admin, true},
+		{`{base "N=3, n=1"}`, `n=6,n=3,n=1,` + strings.ToUpper(admin), true},
+		{`{base "n=3,n=1"}`, `n=1,` + admin, false},
+		{`{specificExclusions { chopBefore "n=2" }}`, `n=2,` + admin, false},
+		{`{specificExclusions { chopBefore "n=2" }}`, `n=5,n=2,` + admin, false},
+		{`{specificExclusions { chopBefore "n=2" }}`, `n=1,` + admin, true},
+		{`{specificExclusions { chopAfter "n=2" }}`, `n=2,` + admin, true},
+		{`{specificExclusions { chopAfter "n=2" }}`, `n=5,n=2,` + admin, false},
+	} {
+		ss, _ := NewSubtreeSpecification(tst.raw)
+		if got, err := ss.Includes(admin, tst.dn); err != nil {
+			t.Errorf("%s[%d] failed: %v", t.Name(), idx, err)
+			return
+		} else if got != tst.want {
+			t.Errorf("%s[%d] failed: want %t, got %t (%s :: %s)",
+				t.Name(), idx, tst.want, got, tst.raw, tst.dn)
+			return
+		}
+	}
+
+	iso := myDedicatedProfile.NewRegistration(true)
+	iso.SetDN(`n=1,` + admin)
+	iso.X680().SetN(`1`)
+	org := iso.NewChild(`3`, `identified-organization`)
+
+	for idx, tst := range []struct {
+		raw  string
+		reg  *Registration
+		want bool
+	}{
+		{`{specificationFilter item:arc}`, org, true},
+		{`{specificationFilter item:arc}`, iso, false},
+		{`{specificationFilter item:` + ObjectClasses[`rootArc`] + `}`, iso, true},
+		{`{specificationFilter and:{item:registration,item:x680Context}}`, org, true},
+		{`{specificationFilter and:{item:registration,not:item:arc}}`, org, false},
+		{`{specificationFilter or:{item:rootArc,item:arc}}`, iso, true},
+		{`{specificationFilter not:item:spatialContext}`, org, true},
+	} {
+		ss, _ := NewSubtreeSpecification(tst.raw)
+		if got, err := ss.Includes(admin, tst.reg); err != nil {
+			t.Errorf("%s[%d] failed: %v", t.Name(), idx, err)
+			return
+		} else if got != tst.want {
+			t.Errorf("%s[%d] failed: want %t, got %t (%s :: %s)",
+				t.Name(), idx, tst.want, got, tst.raw, tst.reg.DN())
+			return
+		}
+	}
+
+	ss, _ := NewSubtreeSpecification(`{specificationFilter item:arc}`)
+	if _, err := ss.Includes(admin, org.DN()); err != MissingObjectClassesErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), MissingObjectClassesErr, err)
+		return
+	}
+
+	for _, bogus := range []any{nil, ``, (*Registration)(nil), 1} {
+		if _, err := ss.Includes(admin, bogus); err == nil {
+			t.Errorf("%s failed: expected error for %T", t.Name(), bogus)
+			return
+		}
+	}
+
+	sub, _ := iso.NewSpatialSubentry(`spatialContext`)
+	if governs, err := sub.Governs(org); err != nil || !governs {
+		t.Errorf("%s failed: subentry does not govern child (%v)", t.Name(), err)
+		return
+	} else if governs, _ = sub.Governs(iso); governs {
+		t.Errorf("%s failed: subentry governs its administrative point", t.Name())
+		return
+	}
+
+	// An escaped comma within the subentry's cn must
+	// not be mistaken for the administrative point.
+	if sub, _ = iso.NewSpatialSubentry(`spatial\, context`); sub == nil {
+		t.Errorf("%s failed: subentry not created", t.Name())
+		return
+	} else if governs, err := sub.Governs(org); err != nil || !governs {
+		t.Errorf("%s failed: subentry with escaped cn does not govern child (%v)", t.Name(), err)
+		return
+	}
+
+	// Unknown descriptors must only match themselves.
+	for idx, tc := range []struct {
+		ref  Refinement
+		ocs  []string
+		want bool
+	}{
+		{ItemRefinement(`person`), []string{`top`}, false},
+		{ItemRefinement(`person`), []string{`top`, `person`}, true},
+		{NotRefinement(ItemRefinement(`person`)), []string{`top`, `registration`}, true},
+		{ItemRefinement(`arc`), []string{`1.3.6.1.4.1.56521.101.2.5.3`}, true},
+	} {
+		if got := tc.ref.Match(tc.ocs...); got != tc.want {
+			t.Errorf("%s[%d] failed: want %t, got %t", t.Name(), idx, tc.want, got)
+			return
+		}
+	}
+
+	var nilSub *Subentry
+	if _, err := nilSub.Governs(org); err != NilInstanceErr {
+		t.Errorf("%s failed: want '%v', got '%v'", t.Name(), NilInstanceErr, err)
+		return
+	}
+}
@@ -0,0 +1,190 @@
+package radir
+
+/*
+succ.go contains the authority succession workflow.
+*/
+
+import "reflect"
+
+/*
+TransferAuthority transfers the receiver to the current authority described
+by the input *[Registrant] instance, returning slices of [Modification] that
+describe the resulting changes alongside an error.
+
+Succession is conducted as follows:
+
+  - If no first authority is recorded, the outgoing current authority becomes the first authority, and its tenure is ended
+  - Otherwise, if no sponsor is recorded, the outgoing current authority becomes the sponsor, and its tenure is ended
+  - Otherwise, an error is returned, as the outgoing current authority cannot be retained
+  - The incoming current authority is officiated
+  - A "[registrationModified]" value is added to the receiver
+
+Recorded first authorities and sponsors are never modified. The start time
+of an outgoing current authority is preserved when it becomes the first
+authority or sponsor. Note that a current authority never bears an end time
+(see [EndTimeNotApplicableErr]), which is why it must assume another role in
+order to retain its history.
+
+All end and start times, as well as the "[registrationModified]" value, are
+derived from the audit clock of the governing *[DITProfile] (see [DITProfile.SetAuditClock]).
+The history limit declared through [DITProfile.UseAuditStamps], if any, is
+honored.
+
+Under the terms of the "Combined Registrants Policy", the current authority
+values of the incoming instance are transcribed into the receiver, and the
+start time of the incoming current authority is assigned.
+
+Under the terms of the "Dedicated Registrants Policy", the incoming instance
+must bear a DN, which replaces all "[currentAuthority]" values of the receiver.
+The outgoing DNs are moved to the "[firstAuthority]" or "[sponsor]" values of
+the receiver, and the "[registrationModified]" value marks the end of their
+tenure. As dedicated registrants may be shared by many registrations, neither
+the incoming nor the outgoing registrants are modified.
+
+In either case, the receiver is the sole entry modified, and is modified in
+place. The return value is intended for transmission to the RA DSA.
+
+[registrationModified]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.12
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+*/
+func (r *Registration) TransferAuthority(incoming *Registrant) (mods []Modification, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	} else if incoming.IsZero() {
+		err = NilRegistrantErr
+		return
+	}
+
+	prof := r.Profile()
+	if !prof.Combined() && !prof.Dedicated() {
+		err = RegistrantPolicyErr
+		return
+	}
+
+	ts := prof.auditStamp()
+	before := r.Unmarshal()
+
+	if prof.Combined() {
+		err = r.transferCombined(incoming, ts)
+	} else {
+		err = r.transferDedicated(incoming)
+	}
+
+	if err != nil {
+		return
+	}
+
+	prof.appendModified(r.Supplement(), ts)
+	r.refreshObjectClasses()
+	mods = diffEntries(r.DN(), before, r.Unmarshal())
+
+	return
+}
+
+func (r *Registration) transferCombined(incoming *Registrant, ts string) (err error) {
+	if incoming.R_CA.IsZero() || incoming.R_CA.isEmpty() {
+		err = wrapErr(RegistrantValidityErr, "no current authority values")
+		return
+	}
+
+	x := r.X660()
+	out, fa, sp := x.R_CCAuthy, x.R_CFAuthy, x.R_CSAuthy
+	if !out.IsZero() && !out.isEmpty() {
+		if fa.IsZero() || fa.isEmpty() {
+			retireAuthority(x.CombinedFirstAuthority(), out, ts)
+		} else if sp.IsZero() || sp.isEmpty() {
+			retireAuthority(x.CombinedSponsor(), out, ts)
+		} else {
+			err = wrapErr(RegistrantPolicyErr, "no vacant role to retain the outgoing current authority")
+			return
+		}
+	}
+
+	x.R_CCAuthy = nil
+	ca := x.CombinedCurrentAuthority()
+	transcribeAuthority(ca, incoming.R_CA)
+	ca.R_StartTime = ts
+
+	return
+}
+
+func (r *Registration) transferDedicated(incoming *Registrant) (err error) {
+	if len(incoming.DN()) == 0 {
+		err = InvalidDNErr
+		return
+	}
+
+	x := r.X660()
+	if strInSlice(incoming.DN(), x.R_CAuthyDN) {
+		err = wrapErr(RegistrantValidityErr, "incoming registrant is already the current authority")
+		return
+	}
+
+	if len(x.R_CAuthyDN) > 0 {
+		if len(x.R_FAuthyDN) == 0 {
+			x.R_FAuthyDN = append([]string{}, x.R_CAuthyDN...)
+		} else if len(x.R_SAuthyDN) == 0 {
+			x.R_SAuthyDN = append([]string{}, x.R_CAuthyDN...)
+		} else {
+			err = wrapErr(RegistrantPolicyErr, "no vacant role to retain the outgoing current authority")
+			return
+		}
+	}
+
+	x.R_CAuthyDN = []string{incoming.DN()}
+
+	return
+}
+
+/*
+retireAuthority transcribes the outgoing *[CurrentAuthority] into the
+input *[FirstAuthority] or *[Sponsor] instance, and ends its tenure.
+*/
+func retireAuthority(dst any, out *CurrentAuthority, ts string) {
+	transcribeAuthority(dst, out)
+	switch tv := dst.(type) {
+	case *FirstAuthority:
+		tv.R_EndTime = ts
+	case *Sponsor:
+		tv.R_EndTime = ts
+	}
+}
+
+/*
+transcribeAuthority assigns the values of each exported string and []string
+field of src to the identically-named field of dst, if present. Both inputs
+are pointers to authority struct instances of any kind.
+*/
+func transcribeAuthority(dst, src any) {
+	_, dv, ok := getReflectInstances(dst)
+	if !ok {
+		return
+	}
+	st, sv, ok := getReflectInstances(src)
+	if !ok {
+		return
+	}
+
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		d, s := dv.FieldByName(f.Name), sv.Field(i)
+		if !d.IsValid() || d.Type() != s.Type() {
+			continue
+		}
+
+		switch d.Kind() {
+		case reflect.String:
+			d.SetString(s.String())
+		case reflect.Slice:
+			vals, _ := s.Interface().([]string)
+			d.Set(valOf(append([]string{}, vals...)))
+		}
+	}
+}
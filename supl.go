@@ -43,6 +43,7 @@ type Supplement struct {
 	RC_DiscloseTo []string `ldap:"c-discloseTo;collective"` // RASCHEMA § 2.3.33
 
 	r_DITProfile *DITProfile
+	r_reg        *Registration
 	r_se         bool
 }
 
@@ -59,6 +60,7 @@ func (r *Registration) Supplement() *Supplement {
 		r.R_Extra = new(Supplement)
 		r.R_Extra.r_DITProfile = r.Profile()
 	}
+	r.R_Extra.r_reg = r
 
 	return r.R_Extra
 }
@@ -148,6 +150,21 @@ func (r *Supplement) CreateTimeGetFunc(getfunc GetOrSetFunc) (any, error) {
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `registrationCreated`)
 }
 
+/*
+Created returns the [GeneralizedTime] form of the "[registrationCreated]"
+value assigned to the receiver instance. A zero instance is returned if
+the value is unset or cannot be parsed.
+
+[registrationCreated]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.11
+*/
+func (r *Supplement) Created() (gt GeneralizedTime) {
+	if !r.IsZero() {
+		gt, _ = ParseGeneralizedTime(r.R_Created)
+	}
+
+	return
+}
+
 /*
 SetCreateTime assigns a string generalized time value to the receiver instance.
 */
@@ -173,6 +190,21 @@ func (r *Supplement) ModifyTimeGetFunc(getfunc GetOrSetFunc) (any, error) {
 	return getFieldValueByNameTagAndGoSF(r, getfunc, `registrationModified`)
 }
 
+/*
+Modified returns the [GeneralizedTimes] form of the "[registrationModified]"
+values assigned to the receiver instance, sorted chronologically. Values
+which cannot be parsed are discarded.
+
+[registrationModified]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.12
+*/
+func (r *Supplement) Modified() (gts GeneralizedTimes) {
+	if !r.IsZero() {
+		gts = parseGeneralizedTimes(r.R_Modified)
+	}
+
+	return
+}
+
 /*
 SetModifyTime appends one or more instances of string generalized time
 values to the receiver instance. Note that if a slice is passed as X,
@@ -0,0 +1,247 @@
+package radir
+
+/*
+syntax.go contains the opt-in syntax checks enabled by way of the
+DITProfile.UseSyntaxChecks method.
+*/
+
+import (
+	"net/mail"
+	"net/url"
+)
+
+/*
+SyntaxError describes a value rejected by the opt-in syntax checks enabled
+by way of [DITProfile.UseSyntaxChecks]. The underlying error, which may be
+accessed using [errors.Is] or [errors.Unwrap], shall be one of:
+
+  - [InvalidCountryErr]
+  - [InvalidTelephoneErr]
+  - [InvalidMailErr]
+  - [InvalidURIErr]
+  - [InvalidGTErr]
+  - [InvalidStatusErr]
+*/
+type SyntaxError struct {
+	Type  string // attribute type, e.g.: "firstAuthorityTelephone"
+	Value string // offending value
+	Err   error  // underlying error
+}
+
+/*
+Error returns the string representation of the receiver instance.
+*/
+func (r *SyntaxError) Error() string {
+	return sprintf("%s: %v: '%s'", r.Type, r.Err, r.Value)
+}
+
+/*
+Unwrap returns the underlying error of the receiver instance.
+*/
+func (r *SyntaxError) Unwrap() error {
+	return r.Err
+}
+
+/*
+IsCountryCode returns a Boolean value indicative of whether the input
+string value is an officially assigned ISO 3166-1 alpha-2 country code.
+*/
+func IsCountryCode(c string) bool {
+	return len(c) == 2 && CountryName(c) != ``
+}
+
+/*
+IsCountryName returns a Boolean value indicative of whether the input
+string value is a known ISO 3166-1 English short country name.
+*/
+func IsCountryName(co string) bool {
+	return CountryCode(co) != ``
+}
+
+/*
+IsTelephoneNumber returns a Boolean value indicative of whether the input
+string value is a telephone number expressed in the international or the
+national notation of [ITU-T Rec. E.123].
+
+Digits may be grouped using single spaces or hyphens. The national notation
+may begin with a parenthesized trunk or area code group, while the international
+notation must begin with a plus sign and a non-zero country code digit. No more
+than fifteen (15) digits, per [ITU-T Rec. E.164], are permitted.
+
+[ITU-T Rec. E.123]: https://www.itu.int/rec/T-REC-E.123
+[ITU-T Rec. E.164]: https://www.itu.int/rec/T-REC-E.164
+*/
+func IsTelephoneNumber(tel string) bool {
+	if len(tel) == 0 {
+		return false
+	}
+
+	var digits int
+	var intl, paren, closed bool
+	last := ' ' // previous character was a separator
+
+	for i, ch := range tel {
+		switch {
+		case ch == '+' && i == 0:
+			intl = true
+			continue
+		case isDigit(ch):
+			if intl && digits == 0 && ch == '0' {
+				return false
+			}
+			digits++
+		case ch == '(' && !intl && i == 0:
+			paren = true
+			continue
+		case ch == ')' && paren && !closed && isDigit(last):
+			closed = true
+		case (ch == ' ' || ch == '-') && isDigit(last) || ch == ' ' && last == ')':
+		default:
+			return false
+		}
+		last = ch
+	}
+
+	return isDigit(last) && paren == closed && 3 <= digits && digits <= 15
+}
+
+/*
+IsMailAddress returns a Boolean value indicative of whether the input string
+value is a bare [RFC 5322] addr-spec, i.e.: one which bears no display name
+or angle brackets.
+
+[RFC 5322]: https://datatracker.ietf.org/doc/html/rfc5322#section-3.4.1
+*/
+func IsMailAddress(addr string) bool {
+	a, err := mail.ParseAddress(addr)
+	return err == nil && a.Name == `` && a.Address == addr
+}
+
+/*
+IsURI returns a Boolean value indicative of whether the input string value
+is an absolute URI, i.e.: one which bears a scheme.
+*/
+func IsURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && len(u.Scheme) > 0 &&
+		(len(u.Host) > 0 || len(u.Opaque) > 0 || len(u.Path) > 0) &&
+		idxr(uri, ' ') == -1
+}
+
+/*
+IsRegistrationStatus returns a Boolean value indicative of whether the input
+string value is one of the status values defined in [RFC 2578 § 2], namely
+"current", "deprecated" or "obsolete".
+
+[RFC 2578 § 2]: https://datatracker.ietf.org/doc/html/rfc2578#section-2
+*/
+func IsRegistrationStatus(status string) bool {
+	return strInSlice(status, []string{`current`, `deprecated`, `obsolete`})
+}
+
+/*
+syntaxChecks returns a Boolean value indicative of whether the input
+instance is subject to opt-in syntax checks.
+*/
+func syntaxChecks(instance any) (check bool) {
+	switch tv := instance.(type) {
+	case *FirstAuthority:
+		check = tv.r_syntax
+	case *CurrentAuthority:
+		check = tv.r_syntax
+	case *Sponsor:
+		check = tv.r_syntax
+	case *Supplement:
+		check = !tv.r_DITProfile.IsZero() && tv.r_DITProfile.r_syntax
+	}
+
+	return
+}
+
+/*
+checkSyntax returns an instance of *[SyntaxError] if any of the string
+values in value do not conform to the syntax associated with tag. Zero
+string values, which are used to clear fields, are not checked.
+*/
+func checkSyntax(tag string, value any) (err error) {
+	var vals []string
+	switch tv := value.(type) {
+	case string:
+		vals = []string{tv}
+	case []string:
+		vals = tv
+	}
+
+	var valid func(string) bool
+	var cause error
+
+	switch t := lc(tag); {
+	case t == `c` || hasSfx(t, `countrycode`):
+		valid, cause = IsCountryCode, InvalidCountryErr
+	case t == `co` || hasSfx(t, `countryname`):
+		valid, cause = IsCountryName, InvalidCountryErr
+	case t == `telephonenumber` || t == `facsimiletelephonenumber` ||
+		hasSfx(t, `telephone`) || hasSfx(t, `fax`) || hasSfx(t, `mobile`):
+		valid, cause = IsTelephoneNumber, InvalidTelephoneErr
+	case t == `mail` || hasSfx(t, `email`):
+		valid, cause = IsMailAddress, InvalidMailErr
+	case t == `labeleduri`:
+		valid, cause = func(v string) bool {
+			// labeledURI := URI [ SP label ]
+			return IsURI(split(v, ` `)[0])
+		}, InvalidURIErr
+	case hasSfx(t, `uri`):
+		valid, cause = IsURI, InvalidURIErr
+	case isGeneralizedTimeType(t):
+		valid, cause = func(v string) bool {
+			_, err := ParseGeneralizedTime(v)
+			return err == nil
+		}, InvalidGTErr
+	case t == `registrationstatus`:
+		valid, cause = IsRegistrationStatus, InvalidStatusErr
+	default:
+		return
+	}
+
+	for i := 0; i < len(vals) && err == nil; i++ {
+		if v := vals[i]; len(v) > 0 && !valid(v) {
+			err = &SyntaxError{Type: tag, Value: v, Err: cause}
+		}
+	}
+
+	return
+}
+
+/*
+countryFields is satisfied by *[FirstAuthority], *[CurrentAuthority] and
+*[Sponsor].
+*/
+type countryFields interface {
+	C() string
+	CO() string
+	SetC(...any) error
+	SetCO(...any) error
+}
+
+/*
+fillCountry assigns the country name corresponding to a freshly-assigned
+country code, or vice versa, to the input instance if the counterpart is
+not already set.
+*/
+func fillCountry(x countryFields, tag string, value any) {
+	v, ok := value.(string)
+	if !ok || len(v) == 0 {
+		return
+	}
+
+	switch t := lc(tag); {
+	case t == `c` || hasSfx(t, `countrycode`):
+		if x.CO() == `` {
+			x.SetCO(CountryName(v))
+		}
+	case t == `co` || hasSfx(t, `countryname`):
+		if x.C() == `` {
+			x.SetC(CountryCode(v))
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package radir
+
+/*
+tenure.go contains point-in-time authority queries and timelines.
+*/
+
+import "time"
+
+/*
+AuthorityRole describes the role of an authority with respect to a
+*[Registration].
+*/
+type AuthorityRole uint8
+
+const (
+	FirstAuthorityRole   AuthorityRole = iota // see *[FirstAuthority]
+	CurrentAuthorityRole                      // see *[CurrentAuthority]
+	SponsorRole                               // see *[Sponsor]
+)
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r AuthorityRole) String() (s string) {
+	switch r {
+	case FirstAuthorityRole:
+		s = `firstAuthority`
+	case CurrentAuthorityRole:
+		s = `currentAuthority`
+	case SponsorRole:
+		s = `sponsor`
+	}
+
+	return
+}
+
+/*
+AuthorityTenure describes the period of time during which an authority
+served a particular role for a *[Registration].
+*/
+type AuthorityTenure struct {
+	DN         string        // DN of the registration
+	Role       AuthorityRole // role served by the authority
+	Registrant string        // DN of the registrant ("Dedicated Registrants Policy" only)
+	Authority  any           // *[FirstAuthority], *[CurrentAuthority] or *[Sponsor]
+	Start      time.Time     // zero if not known
+	End        time.Time     // zero if not known or still open
+}
+
+/*
+Name returns the common name of the underlying authority, or a zero string
+if unset.
+*/
+func (r AuthorityTenure) Name() (cn string) {
+	if auth, ok := r.Authority.(authority); ok {
+		cn = auth.CN()
+	}
+
+	return
+}
+
+/*
+Active returns a Boolean value indicative of whether the receiver instance
+was in effect at the input time. A tenure without a known start time is
+assumed to have been in effect since the beginning of time, while a tenure
+without a known end time is assumed to remain in effect indefinitely.
+*/
+func (r AuthorityTenure) Active(t time.Time) bool {
+	return (r.Start.IsZero() || !t.Before(r.Start)) &&
+		(r.End.IsZero() || t.Before(r.End))
+}
+
+/*
+AuthorityEvent describes the start or end of an [AuthorityTenure] at
+a particular time, as reported by [Registration.AuthorityTimeline].
+*/
+type AuthorityEvent struct {
+	Time   time.Time
+	Ended  bool // false if the event marks the start of the tenure
+	Tenure AuthorityTenure
+}
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r AuthorityEvent) String() (s string) {
+	action := `started`
+	if r.Ended {
+		action = `ended`
+	}
+
+	s = sprintf("%s: %s %s '%s' %s", t2gt(r.Time), r.Tenure.DN,
+		r.Tenure.Role, r.Tenure.Name(), action)
+
+	return
+}
+
+/*
+Tenures returns slices of [AuthorityTenure], each describing an authority
+of the receiver.
+
+Under the terms of the "Combined Registrants Policy", the authorities are
+those embedded within the receiver. Under the terms of the "Dedicated
+Registrants Policy", the "[firstAuthority]", "[currentAuthority]" and
+"[sponsor]" DN values of the receiver are sought among the input [Registrants]
+instance, and the corresponding authority of each registrant found is used.
+Unresolved DNs are not reported.
+
+Start and end times are derived from the generalized time values of each
+authority. Values which cannot be parsed are treated as unknown.
+
+Collective values are not considered; see [Registration.Effective] for a
+means of obtaining an instance which includes such values.
+
+[firstAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.54
+[currentAuthority]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.35
+[sponsor]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.74
+*/
+func (r *Registration) Tenures(ants Registrants) (tenures []AuthorityTenure) {
+	if r.IsZero() || r.R_X660.IsZero() {
+		return
+	}
+
+	x := r.R_X660
+	add := func(role AuthorityRole, registrant string, auth any) {
+		if auth == nil {
+			return
+		}
+
+		tenure := AuthorityTenure{
+			DN:         r.DN(),
+			Role:       role,
+			Registrant: registrant,
+			Authority:  auth,
+		}
+
+		switch tv := auth.(type) {
+		case *FirstAuthority:
+			tenure.Start, tenure.End = tv.Start().Time(), tv.End().Time()
+		case *CurrentAuthority:
+			tenure.Start = tv.Start().Time()
+		case *Sponsor:
+			tenure.Start, tenure.End = tv.Start().Time(), tv.End().Time()
+		}
+
+		tenures = append(tenures, tenure)
+	}
+
+	if r.Profile().Combined() {
+		for role, auth := range []any{x.R_CFAuthy, x.R_CCAuthy, x.R_CSAuthy} {
+			if r.combinedAuthority(role) {
+				add(AuthorityRole(role), ``, auth)
+			}
+		}
+		return
+	}
+
+	for role, dns := range [][]string{x.R_FAuthyDN, x.R_CAuthyDN, x.R_SAuthyDN} {
+		for _, dn := range dns {
+			if ant := ants.getDN(dn); ant != nil {
+				add(AuthorityRole(role), ant.DN(), ant.authority(role))
+			}
+		}
+	}
+
+	return
+}
+
+/*
+AuthoritiesAt returns slices of [AuthorityTenure], each describing an
+authority of the receiver which was in effect at the input time. See
+[Registration.Tenures] for details regarding the input [Registrants].
+
+If one or more [AuthorityRole] values are input, only tenures of those
+roles are returned.
+*/
+func (r *Registration) AuthoritiesAt(t time.Time, ants Registrants, roles ...AuthorityRole) (tenures []AuthorityTenure) {
+	for _, tenure := range r.Tenures(ants) {
+		if !tenure.Active(t) {
+			continue
+		}
+
+		want := len(roles) == 0
+		for i := 0; i < len(roles) && !want; i++ {
+			want = roles[i] == tenure.Role
+		}
+
+		if want {
+			tenures = append(tenures, tenure)
+		}
+	}
+
+	return
+}
+
+/*
+AuthorityTimeline returns slices of [AuthorityEvent], each describing
+the start or end of an [AuthorityTenure] of the receiver or any of its
+ancestors, in chronological order. Tenures of the receiver precede those
+of its ancestors when events occur at the same time.
+
+See [Registration.Tenures] for details regarding the input [Registrants].
+Tenures lacking a known start or end time do not produce the respective
+event.
+*/
+func (r *Registration) AuthorityTimeline(ants Registrants) (events []AuthorityEvent) {
+	for reg := r; !reg.IsZero(); reg = reg.Parent() {
+		for _, tenure := range reg.Tenures(ants) {
+			if !tenure.Start.IsZero() {
+				events = append(events, AuthorityEvent{Time: tenure.Start, Tenure: tenure})
+			}
+			if !tenure.End.IsZero() {
+				events = append(events, AuthorityEvent{Time: tenure.End, Ended: true, Tenure: tenure})
+			}
+		}
+	}
+
+	stabSort(authorityEvents(events))
+
+	return
+}
+
+/*
+authorityEvents implements [sort.Interface] for chronological order of
+[AuthorityEvent] instances.
+*/
+type authorityEvents []AuthorityEvent
+
+func (r authorityEvents) Len() int           { return len(r) }
+func (r authorityEvents) Less(i, j int) bool { return r[i].Time.Before(r[j].Time) }
+func (r authorityEvents) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
@@ -0,0 +1,218 @@
+package radir
+
+/*
+ttl.go contains the resolution of effective time-to-live values.
+*/
+
+import "time"
+
+/*
+TTLSource describes the origin of the value of a [ResolvedTTL].
+*/
+type TTLSource uint8
+
+const (
+	NoTTL         TTLSource = iota // no value was found
+	LiteralTTL                     // "rATTL" value of the entry itself
+	CollectiveTTL                  // "c-rATTL" value of the entry or a governing subentry
+	ProfileTTL                     // "rATTL" value of the governing *DITProfile
+)
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r TTLSource) String() (s string) {
+	switch r {
+	case NoTTL:
+		s = `none`
+	case LiteralTTL:
+		s = `literal`
+	case CollectiveTTL:
+		s = `collective`
+	case ProfileTTL:
+		s = `profile`
+	}
+
+	return
+}
+
+/*
+ResolvedTTL describes the effective time-to-live of an entry, as returned by
+[Registration.ResolveTTL], [Registrant.ResolveTTL] and [Subentry.ResolveTTL].
+*/
+type ResolvedTTL struct {
+	Duration time.Duration // zero if the entry is not to be cached, or if no value was found
+	Source   TTLSource     // origin of the value
+	DN       string        // DN of the entry or profile bearing the value, if known
+	Value    string        // raw value
+}
+
+/*
+String returns the string representation of the receiver instance.
+*/
+func (r ResolvedTTL) String() (s string) {
+	if s = r.Source.String(); r.Source != NoTTL {
+		s = sprintf("%s (%s", r.Duration, s)
+		if len(r.DN) > 0 {
+			s += ` via ` + r.DN
+		}
+		s += `)`
+	}
+
+	return
+}
+
+/*
+Cacheable returns a Boolean value indicative of whether the receiver
+permits the entry to be cached, which is the case if a non-zero value
+was found.
+*/
+func (r ResolvedTTL) Cacheable() bool {
+	return r.Source != NoTTL && r.Duration > 0
+}
+
+/*
+CacheControl returns the HTTP "Cache-Control" header value corresponding
+to the receiver, e.g.: "max-age=3600" or, if the receiver is not cacheable,
+"no-store".
+*/
+func (r ResolvedTTL) CacheControl() (cc string) {
+	cc = `no-store`
+	if r.Cacheable() {
+		cc = `max-age=` + itoa(int(r.Duration/time.Second))
+	}
+
+	return
+}
+
+/*
+ResolveTTL returns the effective time-to-live of the receiver alongside an
+error, as directed by [Section 2.2.3.4 of the RADUA I-D]. The first of the
+following sources to bear a value prevails:
+
+  - The "[rATTL]" value of the receiver
+  - The "[c-rATTL]" value of the receiver, such as one returned by a DSA supporting collective attributes
+  - The "[c-rATTL]" value of the nearest *[Subentry] governing the receiver, unless excluded by the receiver's "[collectiveExclusions]"
+  - The "[rATTL]" value of the *[DITProfile] of the receiver
+
+Candidate subentries are gathered as described by [Registration.Effective],
+including any variadic *[Subentry] input instances.
+
+Values are non-negative integers denoting a number of seconds, wherein zero
+(0) indicates the entry is not to be cached. An error is returned if the
+prevailing value is malformed, in which case subsequent sources are not
+consulted, or if a candidate subentry cannot be evaluated.
+
+[Section 2.2.3.4 of the RADUA I-D]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-radua#section-2.2.3.4
+[rATTL]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.100
+[c-rATTL]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.101
+[collectiveExclusions]: https://www.rfc-editor.org/rfc/rfc3671.html#section-2.1
+*/
+func (r *Registration) ResolveTTL(subentries ...*Subentry) (ttl ResolvedTTL, err error) {
+	if r.IsZero() {
+		err = NilRegistrationErr
+		return
+	}
+
+	if len(r.R_TTL) > 0 {
+		return resolveTTL(LiteralTTL, r.DN(), r.R_TTL)
+	} else if len(r.RC_TTL) > 0 {
+		return resolveTTL(CollectiveTTL, r.DN(), r.RC_TTL)
+	}
+
+	if !r.collectiveExcluded()(`c-rATTL`) {
+		for _, se := range r.candidateSubentries(subentries...) {
+			if len(se.RC_TTL) == 0 {
+				continue
+			}
+
+			var governs bool
+			if governs, err = se.Governs(r); err != nil {
+				return
+			} else if governs {
+				return resolveTTL(CollectiveTTL, se.DN(), se.RC_TTL)
+			}
+		}
+	}
+
+	return resolveProfileTTL(r.R_DITProfile)
+}
+
+/*
+ResolveTTL returns the effective time-to-live of the receiver alongside an
+error. The first of the following sources to bear a value prevails:
+
+  - The "[rATTL]" value of the receiver
+  - The "[c-rATTL]" value of the receiver, such as one returned by a DSA supporting collective attributes
+  - The "[rATTL]" value of the *[DITProfile] of the receiver
+
+See [Registration.ResolveTTL] for details regarding values and errors.
+
+[rATTL]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.100
+[c-rATTL]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.101
+*/
+func (r *Registrant) ResolveTTL() (ttl ResolvedTTL, err error) {
+	if r.IsZero() {
+		err = NilRegistrantErr
+		return
+	}
+
+	if len(r.R_TTL) > 0 {
+		return resolveTTL(LiteralTTL, r.DN(), r.R_TTL)
+	} else if len(r.RC_TTL) > 0 {
+		return resolveTTL(CollectiveTTL, r.DN(), r.RC_TTL)
+	}
+
+	return resolveProfileTTL(r.R_DITProfile)
+}
+
+/*
+ResolveTTL returns the effective time-to-live of the receiver entry itself
+alongside an error. The "[rATTL]" value of the receiver prevails over that
+of its *[DITProfile]. Note that the "[c-rATTL]" value of the receiver applies
+to the entries it governs, rather than to the receiver.
+
+See [Registration.ResolveTTL] for details regarding values and errors.
+
+[rATTL]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.100
+[c-rATTL]: https://datatracker.ietf.org/doc/html/draft-coretta-oiddir-schema#section-2.3.101
+*/
+func (r *Subentry) ResolveTTL() (ttl ResolvedTTL, err error) {
+	if r.IsZero() {
+		err = NilInstanceErr
+		return
+	}
+
+	if len(r.R_TTL) > 0 {
+		return resolveTTL(LiteralTTL, r.DN(), r.R_TTL)
+	}
+
+	return resolveProfileTTL(r.r_DITProfile)
+}
+
+func resolveProfileTTL(prof *DITProfile) (ttl ResolvedTTL, err error) {
+	if !prof.IsZero() && len(prof.R_TTL) > 0 {
+		ttl, err = resolveTTL(ProfileTTL, prof.R_DN, prof.R_TTL)
+	}
+
+	return
+}
+
+/*
+resolveTTL returns an instance of [ResolvedTTL] bearing the input source,
+DN and raw value, alongside an error following an attempt to parse the
+raw value as a non-negative number of seconds.
+*/
+func resolveTTL(src TTLSource, dn, raw string) (ttl ResolvedTTL, err error) {
+	ttl = ResolvedTTL{Source: src, DN: dn, Value: raw}
+
+	secs, aerr := atoi(trimS(raw))
+	if aerr != nil || secs < 0 || int64(secs) > int64(1<<63-1)/int64(time.Second) {
+		err = wrapErr(InvalidTTLErr, src.String()+" value '"+raw+"'")
+		return
+	}
+
+	ttl.Duration = time.Duration(secs) * time.Second
+
+	return
+}
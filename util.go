@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -38,8 +39,10 @@ var (
 	trimL      func(string, string) string         = strings.TrimLeft
 	trimR      func(string, string) string         = strings.TrimRight
 	trimPfx    func(string, string) string         = strings.TrimPrefix
+	trimSfx    func(string, string) string         = strings.TrimSuffix
 	replaceAll func(string, string, string) string = strings.ReplaceAll
 	stabSort   func(sort.Interface)                = sort.Stable
+	sortStrs   func([]string)                      = sort.Strings
 
 	isLetter func(rune) bool = unicode.IsLetter
 	isDigit  func(rune) bool = unicode.IsDigit
@@ -216,7 +219,8 @@ func derefPtr(v reflect.Value) reflect.Value {
 }
 
 func unmarshalSkipField(tag string, t reflect.StructField) bool {
-	return !t.IsExported() || hasPfx(tag, `c-`) || hasSfx(tag, `;collective`)
+	return !t.IsExported() || t.Name == "R_DITProfile" ||
+		hasPfx(tag, `c-`) || hasSfx(tag, `;collective`)
 }
 
 func atobig(n string) (bint *big.Int, ok bool) {
@@ -314,8 +318,11 @@ func getAttributeTypeFieldTags(instance any) (tags []string) {
 		t = t.Elem()
 	}
 	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		tags = append(tags, field.Tag.Get("ldap"))
+		// skip untagged (internal) fields, such as
+		// the subentry indicator of *Spatial.
+		if tag := t.Field(i).Tag.Get("ldap"); len(tag) > 0 {
+			tags = append(tags, tag)
+		}
 	}
 
 	return
@@ -379,6 +386,8 @@ func writeFieldByTag(tag string, funk func(...any) error, instance any, args ...
 		inSubentry = tv.r_se
 	case *Supplement:
 		inSubentry = tv.r_se
+	case *Subentry:
+		inSubentry = true
 	}
 
 	if !inSubentry && isCollectiveTag(tag) {
@@ -389,7 +398,10 @@ func writeFieldByTag(tag string, funk func(...any) error, instance any, args ...
 	}
 
 	if Y == nil {
-		return writeValue(instance, X, tag)
+		if err = writeValue(instance, X, tag); err == nil {
+			stampModified(instance, tag)
+		}
+		return err
 	}
 
 	var v any
@@ -431,6 +443,15 @@ func writeValue(instance, value any, tag string) error {
 				specialHandling(tag, v, instance)
 			}
 		}
+	case GeneralizedTime, time.Time:
+		err = UnsupportedInputTypeErr
+		if isGeneralizedTimeType(tag) {
+			gt, _ := v.(GeneralizedTime)
+			if t, ok := v.(time.Time); ok {
+				gt = GeneralizedTime(t)
+			}
+			err = writeValue(instance, gt.String(), tag)
+		}
 	default:
 		err = UnsupportedInputTypeErr
 	}
@@ -503,6 +524,10 @@ func writeEligible(tag string, value, instance any) (err error) {
 		err = tv.writeEligible(tag, value)
 	}
 
+	if err == nil && syntaxChecks(instance) {
+		err = checkSyntax(tag, value)
+	}
+
 	return
 }
 
@@ -540,6 +565,10 @@ func specialHandling(tag string, value, instance any) {
 	switch tv := instance.(type) {
 	case *X680:
 		tv.specialHandling(tag, value)
+	case *FirstAuthority, *CurrentAuthority, *Sponsor:
+		if syntaxChecks(tv) {
+			fillCountry(tv.(countryFields), tag, value)
+		}
 	}
 }
 
@@ -635,3 +664,70 @@ func splitUnescaped(str, sep, esc string) (slice []string) {
 
 	return
 }
+
+/*
+overlayCollective writes each non-zero collective field value found within
+src into the identically tagged field of dst, unless the excluded function
+returns true for the relevant attribute type descriptor. String fields are
+only written if zero within dst, while string slice fields are merged into
+freshly allocated slices, thus never disturbing any slice shared with dst.
+*/
+func overlayCollective(dst, src any, excluded func(string) bool) {
+	sv := valOf(src)
+	if sv.Kind() != reflect.Ptr || sv.IsNil() || sv.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	st := typeOf(src).Elem()
+	for i := 0; i < st.NumField(); i++ {
+		tag := st.Field(i).Tag.Get("ldap")
+		if !hasSfx(lc(tag), `;collective`) || excluded(tag[:idxr(tag, ';')]) {
+			continue
+		}
+
+		df, err := getFieldByNameTag(dst, tag)
+		if err != nil || !df.CanSet() {
+			continue
+		}
+
+		switch sf := sv.Elem().Field(i); sf.Kind() {
+		case reflect.String:
+			if df.Kind() == reflect.String && len(df.String()) == 0 {
+				df.SetString(sf.String())
+			}
+		case reflect.Slice:
+			svals, _ := sf.Interface().([]string)
+			dvals, ok := df.Interface().([]string)
+			if !ok || len(svals) == 0 {
+				continue
+			}
+
+			merged := append([]string{}, dvals...)
+			for _, val := range svals {
+				if !strInSlice(val, merged) {
+					merged = append(merged, val)
+				}
+			}
+			df.Set(valOf(merged))
+		}
+	}
+}
+
+/*
+writeTagValues writes vals into the field of instance bearing tag, which
+may be of string or []string kind, bypassing any collective write guard.
+*/
+func writeTagValues(instance any, tag string, vals []string) {
+	if instance == nil || len(vals) == 0 {
+		return
+	}
+
+	if f, err := getFieldByNameTag(instance, tag); err == nil && f.CanSet() {
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString(vals[0])
+		case reflect.Slice:
+			f.Set(valOf(append([]string{}, vals...)))
+		}
+	}
+}
@@ -45,6 +45,7 @@ type X660 struct {
 
 	r_DITProfile *DITProfile
 	r_root       *registeredRoot // linked from *Registration during init
+	r_reg        *Registration   // owning registration, if not within a Subentry
 	r_se         bool
 }
 
@@ -61,6 +62,7 @@ func (r *Registration) X660() *X660 {
 		r.R_X660.r_DITProfile = r.Profile()
 		r.R_X660.r_root = r.r_root
 	}
+	r.R_X660.r_reg = r
 
 	return r.R_X660
 }
@@ -108,7 +110,12 @@ func (r *X660) isEmpty() bool {
 
 func (r *X660) ldif() (l string) {
 	if !r.IsZero() {
-		l = toLDIF(r)
+		// Include any authorities embedded by way
+		// of the "Combined Registrants Policy".
+		l = toLDIF(r) +
+			r.R_CFAuthy.ldif() +
+			r.R_CCAuthy.ldif() +
+			r.R_CSAuthy.ldif()
 	}
 
 	return
@@ -592,7 +599,9 @@ func (r *X660) CombinedFirstAuthority() (dr *FirstAuthority) {
 	if r.R_CFAuthy.IsZero() {
 		r.R_CFAuthy = new(FirstAuthority)
 		r.R_CFAuthy.r_alt_types = r.profile().r_alt_types
+		r.R_CFAuthy.r_syntax = r.profile().r_syntax
 	}
+	r.R_CFAuthy.r_reg = r.r_reg
 
 	return r.R_CFAuthy
 }
@@ -612,7 +621,9 @@ func (r *X660) CombinedCurrentAuthority() *CurrentAuthority {
 	if r.R_CCAuthy.IsZero() {
 		r.R_CCAuthy = new(CurrentAuthority)
 		r.R_CCAuthy.r_alt_types = r.profile().r_alt_types
+		r.R_CCAuthy.r_syntax = r.profile().r_syntax
 	}
+	r.R_CCAuthy.r_reg = r.r_reg
 
 	return r.R_CCAuthy
 }
@@ -632,7 +643,9 @@ func (r *X660) CombinedSponsor() *Sponsor {
 	if r.R_CSAuthy.IsZero() {
 		r.R_CSAuthy = new(Sponsor)
 		r.R_CSAuthy.r_alt_types = r.profile().r_alt_types
+		r.R_CSAuthy.r_syntax = r.profile().r_syntax
 	}
+	r.R_CSAuthy.r_reg = r.r_reg
 
 	return r.R_CSAuthy
 }
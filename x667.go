@@ -20,6 +20,7 @@ type X667 struct {
 
 	r_DITProfile *DITProfile
 	r_root       *registeredRoot
+	r_reg        *Registration
 }
 
 /*
@@ -35,6 +36,7 @@ func (r *Registration) X667() *X667 {
 		r.R_X667.r_DITProfile = r.Profile()
 		r.R_X667.r_root = r.r_root
 	}
+	r.R_X667.r_reg = r
 
 	return r.R_X667
 }
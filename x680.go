@@ -28,6 +28,7 @@ type X680 struct {
 	R_IRI        []string `ldap:"iRI"`               // RASCHEMA § 2.3.3
 	r_DITProfile *DITProfile
 	r_root       *registeredRoot
+	r_reg        *Registration
 }
 
 /*
@@ -43,6 +44,7 @@ func (r *Registration) X680() *X680 {
 		r.R_X680.r_DITProfile = r.Profile()
 		r.R_X680.r_root = r.r_root
 	}
+	r.R_X680.r_reg = r
 
 	return r.R_X680
 }
@@ -19,6 +19,7 @@ type X690 struct {
 	R_DotEnc     string `ldap:"dotEncoding"` // RASCHEMA § 2.3.103
 	r_DITProfile *DITProfile
 	r_root       *registeredRoot
+	r_reg        *Registration
 }
 
 /*
@@ -34,6 +35,7 @@ func (r *Registration) X690() *X690 {
 		r.R_X690.r_DITProfile = r.Profile()
 		r.R_X690.r_root = r.r_root
 	}
+	r.R_X690.r_reg = r
 
 	return r.R_X690
 }